@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// bloomDefaultRate is the false-positive rate used when the configured one is invalid.
+	bloomDefaultRate = 0.01
+
+	// bloomMaxBits is the size limit of Redis bitmaps.
+	bloomMaxBits = 1 << 32
+)
+
+// BloomFilter defines the interface for a probabilistic set membership filter.
+// Items that were added are always reported as present, while items that were not
+// may be reported as present at the configured false-positive rate.
+type BloomFilter interface {
+	// Add inserts items into the filter.
+	// Returns an error if the operation fails.
+	Add(items ...string) error
+
+	// Contains reports whether item may have been added to the filter.
+	// Returns an error if the operation fails.
+	Contains(item string) (bool, error)
+
+	// Clear removes all items from the filter.
+	// Returns an error if the operation fails.
+	Clear() error
+}
+
+// bloomBackend stores the bits of a single Bloom filter.
+type bloomBackend interface {
+	// set sets the bits at the given offsets.
+	set(offsets []uint64) error
+
+	// test reports whether all bits at the given offsets are set.
+	test(offsets []uint64) (bool, error)
+
+	// clear resets all bits.
+	clear() error
+}
+
+// bloomFilter is the concrete implementation of the BloomFilter interface over a bloomBackend.
+type bloomFilter struct {
+	name    string
+	bits    uint64
+	hashes  uint64
+	backend bloomBackend
+	opt     option
+}
+
+// newBloomFilter creates a Bloom filter sized for capacity items at the given
+// false-positive rate over the given backend.
+func newBloomFilter(name string, capacity uint64, rate float64, backend func(bits uint64) bloomBackend, opts ...Option) *bloomFilter {
+	bits, hashes := bloomSize(capacity, rate)
+	return &bloomFilter{
+		name:    name,
+		bits:    bits,
+		hashes:  hashes,
+		backend: backend(bits),
+		opt:     newOption(opts...),
+	}
+}
+
+func (b *bloomFilter) Add(items ...string) (err error) {
+	defer b.opt.observe("bloom_add", b.name, time.Now(), &err)
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	offsets := make([]uint64, 0, len(items)*int(b.hashes))
+	for _, item := range items {
+		offsets = append(offsets, b.offsets(item)...)
+	}
+
+	return b.backend.set(offsets)
+}
+
+func (b *bloomFilter) Contains(item string) (_ bool, err error) {
+	defer b.opt.observe("bloom_contains", b.name, time.Now(), &err)
+
+	return b.backend.test(b.offsets(item))
+}
+
+func (b *bloomFilter) Clear() (err error) {
+	defer b.opt.observe("bloom_clear", b.name, time.Now(), &err)
+
+	return b.backend.clear()
+}
+
+// offsets returns the bit offsets of item using double hashing.
+func (b *bloomFilter) offsets(item string) []uint64 {
+	h1 := seededHash(0, item)
+	h2 := seededHash(1, item) | 1
+
+	offsets := make([]uint64, b.hashes)
+	for i := range offsets {
+		offsets[i] = (h1 + uint64(i)*h2) % b.bits
+	}
+	return offsets
+}
+
+// bloomSize returns the number of bits and hash functions of a filter holding
+// capacity items at the given false-positive rate.
+func bloomSize(capacity uint64, rate float64) (uint64, uint64) {
+	if rate <= 0 || rate >= 1 {
+		rate = bloomDefaultRate
+	}
+
+	n := float64(max(capacity, 1))
+	bits := math.Ceil(-n * math.Log(rate) / (math.Ln2 * math.Ln2))
+	bits = min(bits, bloomMaxBits)
+	hashes := max(math.Round(bits/n*math.Ln2), 1)
+	return uint64(bits), uint64(hashes)
+}
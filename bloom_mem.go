@@ -0,0 +1,47 @@
+package cache
+
+import "sync"
+
+// memBloomBackend is an in-memory bitset implementation of the bloomBackend interface.
+type memBloomBackend struct {
+	words []uint64
+	mutex sync.RWMutex
+}
+
+// NewMemoryBloomFilter creates a new in-memory Bloom filter sized for capacity items
+// at the given false-positive rate, 0.01 if the rate is not between 0 and 1.
+func NewMemoryBloomFilter(capacity uint64, rate float64, opts ...Option) BloomFilter {
+	return newBloomFilter("memory", capacity, rate, func(bits uint64) bloomBackend {
+		return &memBloomBackend{words: make([]uint64, (bits+63)/64)}
+	}, opts...)
+}
+
+func (m *memBloomBackend) set(offsets []uint64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, offset := range offsets {
+		m.words[offset/64] |= 1 << (offset % 64)
+	}
+	return nil
+}
+
+func (m *memBloomBackend) test(offsets []uint64) (bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, offset := range offsets {
+		if m.words[offset/64]&(1<<(offset%64)) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m *memBloomBackend) clear() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	clear(m.words)
+	return nil
+}
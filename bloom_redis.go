@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisBloomBackend is a Redis bitmap implementation of the bloomBackend interface.
+type redisBloomBackend struct {
+	key    string
+	client redis.UniversalClient
+}
+
+// NewRedisBloomFilter creates a new Redis Bloom filter sized for capacity items
+// at the given false-positive rate, 0.01 if the rate is not between 0 and 1.
+// The filter is stored in a bitmap of at most 512 MB, and all instances sharing
+// a name must use the same capacity and rate.
+func NewRedisBloomFilter(name string, capacity uint64, rate float64, client redis.UniversalClient, opts ...Option) BloomFilter {
+	key := newOption(opts...).keyEncoder.Encode("bloom", name)
+	return newBloomFilter(name, capacity, rate, func(uint64) bloomBackend {
+		return &redisBloomBackend{key: key, client: client}
+	}, opts...)
+}
+
+func (r *redisBloomBackend) set(offsets []uint64) error {
+	_, err := r.client.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for _, offset := range offsets {
+			pipe.SetBit(context.Background(), r.key, int64(offset), 1)
+		}
+		return nil
+	})
+	return redisError("bloom_add", r.key, err)
+}
+
+func (r *redisBloomBackend) test(offsets []uint64) (bool, error) {
+	cmds, err := r.client.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for _, offset := range offsets {
+			pipe.GetBit(context.Background(), r.key, int64(offset))
+		}
+		return nil
+	})
+	if err != nil {
+		return false, redisError("bloom_contains", r.key, err)
+	}
+
+	for _, cmd := range cmds {
+		if cmd.(*redis.IntCmd).Val() == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *redisBloomBackend) clear() error {
+	err := r.client.Del(context.Background(), r.key).Err()
+	return redisError("bloom_clear", r.key, err)
+}
@@ -0,0 +1,51 @@
+package cache_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilter(t *testing.T) {
+	name := "emails " + time.Now().Format(time.RFC3339Nano)
+	filters := map[string]cache.BloomFilter{
+		"memory": cache.NewMemoryBloomFilter(1000, 0.01),
+		"redis":  cache.NewRedisBloomFilter(name, 1000, 0.01, redis.NewClient(&redis.Options{})),
+	}
+
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			items := make([]string, 1000)
+			for i := range items {
+				items[i] = "user" + strconv.Itoa(i) + "@example.com"
+			}
+			require.NoError(t, filter.Add(items...))
+
+			for _, item := range items {
+				ok, err := filter.Contains(item)
+				require.NoError(t, err)
+				require.True(t, ok, item)
+			}
+
+			positives := 0
+			for i := range 2000 {
+				ok, err := filter.Contains("other" + strconv.Itoa(i) + "@example.com")
+				require.NoError(t, err)
+				if ok {
+					positives++
+				}
+			}
+			assert.Less(t, positives, 60)
+
+			require.NoError(t, filter.Clear())
+			ok, err := filter.Contains(items[0])
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -63,3 +63,30 @@ type Cache interface {
 	// Returns true if the key exists, and an error if the operation fails.
 	DecrementFloat(key string, value float64) (bool, error)
 }
+
+// Looker is implemented by caches that can tell a stored nil value from a missing
+// key in a single call. Every cache in this package implements it; use the Lookup
+// function to also support other Cache implementations.
+type Looker interface {
+	// Lookup retrieves the value associated with the specified key from the cache.
+	// Returns the value, whether the key exists, and an error if the operation fails.
+	// Unlike Get, it distinguishes a stored nil value from a missing key.
+	Lookup(key string) (any, bool, error)
+}
+
+// Lookup retrieves the value of key and whether the key exists.
+// Caches not implementing Looker are queried with Get, then with Exists when
+// the value is nil, so a key changed in between may be misreported.
+func Lookup(cache Cache, key string) (any, bool, error) {
+	if looker, ok := cache.(Looker); ok {
+		return looker.Lookup(key)
+	}
+
+	val, err := cache.Get(key)
+	if err != nil || val != nil {
+		return val, val != nil, err
+	}
+
+	exists, err := cache.Exists(key)
+	return nil, exists, err
+}
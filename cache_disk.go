@@ -0,0 +1,514 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+// diskRecord represents a single cache entry stored on disk.
+type diskRecord struct {
+	Key    string
+	Data   any
+	Expiry *time.Time
+}
+
+// diskCache is a disk-based cache implementation storing one file per key.
+// Files are named after the SHA-256 hash of their key and spread over
+// two levels of sub-directories to keep directories small.
+type diskCache struct {
+	dir   string
+	mutex sync.Mutex
+	opt   option
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewDiskCache creates and returns a new disk cache instance storing entries under dir.
+// Values are encoded with encoding/gob, custom types must be registered with gob.Register.
+// The cache is safe for concurrent use within a process but not across processes.
+// Expired files are swept every ten minutes unless configured with WithCleanupInterval.
+// Expiry and sweeps follow the clock set with WithClock.
+// The returned cache implements io.Closer to stop the sweep.
+func NewDiskCache(dir string, opts ...Option) (Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &diskCache{
+		dir:  dir,
+		opt:  newOption(opts...),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if interval := safeValue(d.opt.cleanup, 10*time.Minute); interval > 0 {
+		go d.purge(interval)
+	} else {
+		close(d.done)
+	}
+
+	return d, nil
+}
+
+func (d *diskCache) Put(key string, value any, ttl *time.Duration) (err error) {
+	defer d.finish("put", key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var expiry *time.Time
+	if ttl != nil {
+		exp := d.opt.clock.Now().Add(*ttl)
+		expiry = &exp
+	}
+
+	return d.write(&diskRecord{
+		Key:    key,
+		Data:   value,
+		Expiry: expiry,
+	})
+}
+
+func (d *diskCache) Update(key string, value any) (_ bool, err error) {
+	defer d.finish("update", key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.read(key)
+	if err != nil || record == nil {
+		return false, err
+	}
+
+	record.Data = value
+	if err := d.write(record); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (d *diskCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	ok, err := d.Update(key, value)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return d.Put(key, value, ttl)
+	}
+
+	return nil
+}
+
+func (d *diskCache) Get(key string) (any, error) {
+	val, _, err := d.Lookup(key)
+	return val, err
+}
+
+func (d *diskCache) Pull(key string) (any, error) {
+	val, err := d.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Forget(key); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (d *diskCache) Cast(key string) (cast.Caster, error) {
+	val, err := d.Get(key)
+	return cast.NewCaster(val), err
+}
+
+func (d *diskCache) Exists(key string) (_ bool, err error) {
+	defer d.finish("exists", key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.read(key)
+	return record != nil, err
+}
+
+func (d *diskCache) Forget(key string) (err error) {
+	defer d.finish("forget", key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.remove(key)
+}
+
+func (d *diskCache) TTL(key string) (_ time.Duration, err error) {
+	defer d.finish("ttl", key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.read(key)
+	if err != nil || record == nil {
+		return 0, err
+	}
+
+	if record.Expiry == nil {
+		return time.Duration(math.MaxInt64), nil
+	}
+
+	return record.Expiry.Sub(d.opt.clock.Now()), nil
+}
+
+func (d *diskCache) Increment(key string, value int64) (bool, error) {
+	return d.modifyNumericValue("increment", key, value, func(a, b int64) int64 { return a + b })
+}
+
+func (d *diskCache) Decrement(key string, value int64) (bool, error) {
+	return d.modifyNumericValue("decrement", key, value, func(a, b int64) int64 { return a - b })
+}
+
+func (d *diskCache) IncrementFloat(key string, value float64) (bool, error) {
+	return d.modifyFloatValue("increment_float", key, value, func(a, b float64) float64 { return a + b })
+}
+
+func (d *diskCache) DecrementFloat(key string, value float64) (bool, error) {
+	return d.modifyFloatValue("decrement_float", key, value, func(a, b float64) float64 { return a - b })
+}
+
+// Range calls fn for each live key until fn returns false.
+// The keys are collected before fn is called, holding the mutex per file.
+// Undecodable files are logged and skipped.
+func (d *diskCache) Range(fn func(key string) bool) (err error) {
+	defer d.finish("range", "", time.Now(), &err)
+
+	paths, err := d.files()
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for _, path := range paths {
+		record, err := d.load(path)
+		if err != nil {
+			return err
+		}
+
+		if record != nil && !d.expired(record) {
+			keys = append(keys, record.Key)
+		}
+	}
+
+	for _, key := range keys {
+		if !fn(key) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (d *diskCache) Lookup(key string) (_ any, _ bool, err error) {
+	defer d.finish("lookup", key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.read(key)
+	if err != nil || record == nil {
+		return nil, false, err
+	}
+
+	return record.Data, true, nil
+}
+
+// Close stops the background sweep of expired files.
+func (d *diskCache) Close() error {
+	d.once.Do(func() { close(d.stop) })
+	<-d.done
+	return nil
+}
+
+// finish maps the error of a completed operation and logs it.
+// File system errors are wrapped into an OpError as they are.
+func (d *diskCache) finish(op, key string, start time.Time, err *error) {
+	var opErr *OpError
+	if *err != nil && !errors.As(*err, &opErr) {
+		*err = &OpError{Op: op, Key: key, Err: *err}
+	}
+
+	d.opt.observe(op, key, start, err)
+}
+
+// path returns the file path of a key.
+func (d *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(d.dir, name[0:2], name[2:4], name)
+}
+
+// read loads a cache entry from disk, removing it if expired.
+// Returns nil if the key does not exist. It must be called with the mutex held.
+func (d *diskCache) read(key string) (*diskRecord, error) {
+	record, err := readRecord(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Remove expired entries
+	if d.expired(record) {
+		if err := d.remove(key); err != nil {
+			return nil, err
+		}
+
+		d.opt.logger.Debug(
+			"cache entry evicted",
+			slog.String("key", key),
+			slog.String("reason", "expired"),
+		)
+		return nil, nil
+	}
+
+	return record, nil
+}
+
+// write stores a cache entry on disk atomically.
+// It must be called with the mutex held.
+func (d *diskCache) write(record *diskRecord) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(record); err != nil {
+		return fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	path := d.path(record.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// remove deletes the file of a key. It must be called with the mutex held.
+func (d *diskCache) remove(key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// purge removes expired files every interval of the clock until the cache is closed.
+func (d *diskCache) purge(interval time.Duration) {
+	defer close(d.done)
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-d.opt.clock.After(interval):
+		}
+
+		n, err := d.sweep()
+		if err != nil {
+			d.opt.logger.Error("cache purge failed", slog.Any("error", err))
+		}
+
+		if n > 0 {
+			d.opt.logger.Debug(
+				"cache entries evicted",
+				slog.Int("count", n),
+				slog.String("reason", "expired"),
+			)
+		}
+	}
+}
+
+// sweep removes the files of expired entries and returns their number.
+// The mutex is held per file so that the cache stays usable during the sweep.
+// Undecodable files are logged and kept, as they may hold values of types
+// registered by other programs.
+func (d *diskCache) sweep() (int, error) {
+	paths, err := d.files()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, path := range paths {
+		removed, err := d.sweepFile(path)
+		if err != nil {
+			return n, err
+		}
+
+		if removed {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// sweepFile removes the file at path if its entry has expired.
+func (d *diskCache) sweepFile(path string) (bool, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.decode(path)
+	if err != nil || record == nil || !d.expired(record) {
+		return false, err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// files returns the paths of the entry files, walking the directory without the mutex.
+func (d *diskCache) files() ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(d.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			return err
+		}
+
+		paths = append(paths, path)
+		return nil
+	})
+
+	return paths, err
+}
+
+// load reads the entry stored at path with the mutex held.
+func (d *diskCache) load(path string) (*diskRecord, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.decode(path)
+}
+
+// decode reads the entry stored at path. Returns nil for files removed meanwhile
+// and for undecodable files, which are logged. It must be called with the mutex held.
+func (d *diskCache) decode(path string) (*diskRecord, error) {
+	record, err := readRecord(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if errors.Is(err, ErrCodec) {
+		d.opt.logger.Warn(
+			"cache file skipped",
+			slog.String("path", path),
+			slog.Any("error", err),
+		)
+		return nil, nil
+	}
+
+	return record, err
+}
+
+// expired reports whether record has expired according to the clock.
+func (d *diskCache) expired(record *diskRecord) bool {
+	return record.Expiry != nil && record.Expiry.Before(d.opt.clock.Now())
+}
+
+// readRecord decodes the cache entry stored in a file.
+func readRecord(path string) (*diskRecord, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	record := new(diskRecord)
+	if err := gob.NewDecoder(bytes.NewReader(content)).Decode(record); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	return record, nil
+}
+
+// modifyNumericValue is a helper function to modify integer values in the cache.
+func (d *diskCache) modifyNumericValue(name, key string, value int64, op func(int64, int64) int64) (_ bool, err error) {
+	defer d.finish(name, key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.read(key)
+	if err != nil || record == nil {
+		return false, err
+	}
+
+	num, err := cast.NewCaster(record.Data).Int64()
+	if err != nil {
+		return false, &OpError{Op: name, Key: key, Err: ErrNotNumeric}
+	}
+
+	record.Data = op(num, value)
+	if err := d.write(record); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// modifyFloatValue is a helper function to modify float values in the cache.
+func (d *diskCache) modifyFloatValue(name, key string, value float64, op func(float64, float64) float64) (_ bool, err error) {
+	defer d.finish(name, key, time.Now(), &err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	record, err := d.read(key)
+	if err != nil || record == nil {
+		return false, err
+	}
+
+	num, err := cast.NewCaster(record.Data).Float64()
+	if err != nil {
+		return false, &OpError{Op: name, Key: key, Err: ErrNotNumeric}
+	}
+
+	record.Data = op(num, value)
+	if err := d.write(record); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -1,7 +1,8 @@
 package cache
 
 import (
-	"errors"
+	"encoding/gob"
+	"log/slog"
 	"math"
 	"sync"
 	"time"
@@ -19,13 +20,45 @@ type memRecord struct {
 type memCache struct {
 	data  map[string]memRecord
 	mutex sync.RWMutex
+	opt   option
+
+	stop  chan struct{}
+	loops sync.WaitGroup
+	once  sync.Once
+}
+
+func init() {
+	// Data structures are stored as values and must be known to snapshots
+	gob.Register(memHash{})
+	gob.Register(memSet{})
+	gob.Register(memSortedSet{})
 }
 
 // NewMemoryCache creates and returns a new in-memory cache instance.
-func NewMemoryCache() Cache {
-	return &memCache{
+// Expired entries are removed when read, and periodically if WithCleanupInterval is set.
+// The returned cache implements HashCache, SetCache, SortedSetCache,
+// Snapshotter and io.Closer.
+func NewMemoryCache(opts ...Option) Cache {
+	m := &memCache{
 		data: make(map[string]memRecord),
+		opt:  newOption(opts...),
+		stop: make(chan struct{}),
+	}
+
+	if interval := safeValue(m.opt.cleanup, 0); interval > 0 {
+		m.loop(interval, m.sweep)
+	}
+
+	if m.opt.snapshotPath == "" {
+		return m
 	}
+
+	m.loadSnapshot()
+	if m.opt.snapshotInterval > 0 {
+		m.loop(m.opt.snapshotInterval, m.snapshotTick)
+	}
+
+	return m
 }
 
 func (m *memCache) Put(key string, value any, ttl *time.Duration) error {
@@ -34,7 +67,7 @@ func (m *memCache) Put(key string, value any, ttl *time.Duration) error {
 
 	var expiry *time.Time
 	if ttl != nil {
-		exp := time.Now().Add(*ttl)
+		exp := m.opt.clock.Now().Add(*ttl)
 		expiry = &exp
 	}
 
@@ -46,16 +79,16 @@ func (m *memCache) Put(key string, value any, ttl *time.Duration) error {
 }
 
 func (m *memCache) Update(key string, value any) (bool, error) {
-	record, exists := m.read(key)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	record, exists := m.live(key)
 	if !exists {
 		return false, nil
 	}
 
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
 	record.data = value
-	m.data[key] = *record
+	m.data[key] = record
 	return true, nil
 }
 
@@ -73,12 +106,22 @@ func (m *memCache) PutOrUpdate(key string, value any, ttl *time.Duration) error
 }
 
 func (m *memCache) Get(key string) (any, error) {
+	val, _, err := m.Lookup(key)
+	return val, err
+}
+
+func (m *memCache) Lookup(key string) (any, bool, error) {
 	record, exists := m.read(key)
 	if !exists {
-		return nil, nil
+		return nil, false, nil
 	}
 
-	return record.data, nil
+	// Data structures are modified in place and only read through their own methods
+	if isStructure(record.data) {
+		return nil, false, &OpError{Op: "lookup", Key: key, Err: ErrWrongType}
+	}
+
+	return record.data, true, nil
 }
 
 func (m *memCache) Pull(key string) (any, error) {
@@ -122,23 +165,57 @@ func (m *memCache) TTL(key string) (time.Duration, error) {
 		return time.Duration(math.MaxInt64), nil
 	}
 
-	return time.Until(*record.expiry), nil
+	return record.expiry.Sub(m.opt.clock.Now()), nil
 }
 
 func (m *memCache) Increment(key string, value int64) (bool, error) {
-	return m.modifyNumericValue(key, value, func(a, b int64) int64 { return a + b })
+	return m.modifyNumericValue("increment", key, value, func(a, b int64) int64 { return a + b })
 }
 
 func (m *memCache) Decrement(key string, value int64) (bool, error) {
-	return m.modifyNumericValue(key, value, func(a, b int64) int64 { return a - b })
+	return m.modifyNumericValue("decrement", key, value, func(a, b int64) int64 { return a - b })
 }
 
 func (m *memCache) IncrementFloat(key string, value float64) (bool, error) {
-	return m.modifyFloatValue(key, value, func(a, b float64) float64 { return a + b })
+	return m.modifyFloatValue("increment_float", key, value, func(a, b float64) float64 { return a + b })
 }
 
 func (m *memCache) DecrementFloat(key string, value float64) (bool, error) {
-	return m.modifyFloatValue(key, value, func(a, b float64) float64 { return a - b })
+	return m.modifyFloatValue("decrement_float", key, value, func(a, b float64) float64 { return a - b })
+}
+
+// Range calls fn for each live key until fn returns false.
+// Hashes, sets and sorted sets are skipped, like the Redis cache does.
+func (m *memCache) Range(fn func(key string) bool) error {
+	now := m.opt.clock.Now()
+	m.mutex.RLock()
+	keys := make([]string, 0, len(m.data))
+	for key, record := range m.data {
+		if (record.expiry == nil || !record.expiry.Before(now)) && !isStructure(record.data) {
+			keys = append(keys, key)
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, key := range keys {
+		if !fn(key) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Close stops the background tasks of the cache and saves the snapshot file if one is set.
+func (m *memCache) Close() error {
+	m.once.Do(func() { close(m.stop) })
+	m.loops.Wait()
+
+	if m.opt.snapshotPath == "" {
+		return nil
+	}
+
+	return m.saveSnapshot()
 }
 
 // read retrieves a cache entry by key, ensuring thread safety and handling expiry.
@@ -152,21 +229,158 @@ func (m *memCache) read(key string) (*memRecord, bool) {
 	}
 
 	// Remove expired entries
-	if val.expiry != nil && val.expiry.Before(time.Now()) {
+	if val.expiry != nil && val.expiry.Before(m.opt.clock.Now()) {
 		m.mutex.RUnlock()
 		m.mutex.Lock()
 		delete(m.data, key)
 		m.mutex.Unlock()
 		m.mutex.RLock()
+		m.opt.logger.Debug(
+			"cache entry evicted",
+			slog.String("key", key),
+			slog.String("reason", "expired"),
+		)
 		return nil, false
 	}
 
 	return &val, true
 }
 
+// loop runs task every interval of the clock until the cache is closed.
+func (m *memCache) loop(interval time.Duration, task func()) {
+	m.loops.Add(1)
+	go func() {
+		defer m.loops.Done()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-m.opt.clock.After(interval):
+			}
+
+			task()
+		}
+	}()
+}
+
+// sweep removes all expired entries.
+func (m *memCache) sweep() {
+	now := m.opt.clock.Now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, record := range m.data {
+		if record.expiry != nil && record.expiry.Before(now) {
+			delete(m.data, key)
+			m.opt.logger.Debug(
+				"cache entry evicted",
+				slog.String("key", key),
+				slog.String("reason", "expired"),
+			)
+		}
+	}
+}
+
+// isStructure reports whether data is a hash, set or sorted set.
+func isStructure(data any) bool {
+	switch data.(type) {
+	case memHash, memSet, memSortedSet:
+		return true
+	default:
+		return false
+	}
+}
+
+// create stores the value of a new data structure at key.
+// The caller must hold the write lock.
+func (m *memCache) create(key string, data any, ttl *time.Duration) {
+	var expiry *time.Time
+	if ttl != nil {
+		exp := m.opt.clock.Now().Add(*ttl)
+		expiry = &exp
+	}
+
+	m.data[key] = memRecord{
+		data:   data,
+		expiry: expiry,
+	}
+}
+
+// memRead calls fn with the live value of type T stored at key, or the zero value
+// if the key does not exist, holding the read lock. An expired key is removed
+// under the write lock instead.
+func memRead[T any](m *memCache, op, key string, fn func(val T)) error {
+	m.mutex.RLock()
+	record, ok := m.data[key]
+	if !ok || record.expiry == nil || !record.expiry.Before(m.opt.clock.Now()) {
+		defer m.mutex.RUnlock()
+
+		var val T
+		if ok {
+			if val, ok = record.data.(T); !ok {
+				return &OpError{Op: op, Key: key, Err: ErrWrongType}
+			}
+		}
+
+		fn(val)
+		return nil
+	}
+	m.mutex.RUnlock()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	val, err := memValue[T](m, op, key)
+	if err != nil {
+		return err
+	}
+
+	fn(val)
+	return nil
+}
+
+// memValue returns the live value of type T stored at key, or the zero value
+// if the key does not exist. The caller must hold the write lock.
+func memValue[T any](m *memCache, op, key string) (T, error) {
+	var zero T
+	record, ok := m.live(key)
+	if !ok {
+		return zero, nil
+	}
+
+	val, ok := record.data.(T)
+	if !ok {
+		return zero, &OpError{Op: op, Key: key, Err: ErrWrongType}
+	}
+
+	return val, nil
+}
+
+// live returns the record stored at key, removing it if expired.
+// The caller must hold the write lock.
+func (m *memCache) live(key string) (memRecord, bool) {
+	record, ok := m.data[key]
+	if !ok {
+		return memRecord{}, false
+	}
+
+	if record.expiry != nil && record.expiry.Before(m.opt.clock.Now()) {
+		delete(m.data, key)
+		return memRecord{}, false
+	}
+
+	return record, true
+}
+
 // modifyNumericValue is a helper function to modify integer values in the cache.
-func (m *memCache) modifyNumericValue(key string, value int64, op func(int64, int64) int64) (bool, error) {
-	record, exists := m.read(key)
+func (m *memCache) modifyNumericValue(name, key string, value int64, op func(int64, int64) int64) (_ bool, err error) {
+	defer m.opt.observe(name, key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	record, exists := m.live(key)
 	if !exists {
 		return false, nil
 	}
@@ -174,20 +388,22 @@ func (m *memCache) modifyNumericValue(key string, value int64, op func(int64, in
 	caster := cast.NewCaster(record.data)
 	num, err := caster.Int64()
 	if err != nil {
-		return false, errors.New("value is not numeric")
+		return false, &OpError{Op: name, Key: key, Err: ErrNotNumeric}
 	}
 
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
 	record.data = op(num, value)
-	m.data[key] = *record
+	m.data[key] = record
 	return true, nil
 }
 
 // modifyFloatValue is a helper function to modify float values in the cache.
-func (m *memCache) modifyFloatValue(key string, value float64, op func(float64, float64) float64) (bool, error) {
-	record, exists := m.read(key)
+func (m *memCache) modifyFloatValue(name, key string, value float64, op func(float64, float64) float64) (_ bool, err error) {
+	defer m.opt.observe(name, key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	record, exists := m.live(key)
 	if !exists {
 		return false, nil
 	}
@@ -195,13 +411,10 @@ func (m *memCache) modifyFloatValue(key string, value float64, op func(float64,
 	caster := cast.NewCaster(record.data)
 	num, err := caster.Float64()
 	if err != nil {
-		return false, errors.New("value is not numeric")
+		return false, &OpError{Op: name, Key: key, Err: ErrNotNumeric}
 	}
 
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
 	record.data = op(num, value)
-	m.data[key] = *record
+	m.data[key] = record
 	return true, nil
 }
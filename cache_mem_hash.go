@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"maps"
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+// memHash is the value of a hash stored in the memory cache.
+type memHash map[string]any
+
+func (m *memCache) HGet(key, field string) (_ any, err error) {
+	defer m.opt.observe("hget", key, time.Now(), &err)
+
+	var val any
+	err = memRead(m, "hget", key, func(hash memHash) {
+		val = hash[field]
+	})
+	return val, err
+}
+
+func (m *memCache) HSet(key string, values map[string]any, ttl *time.Duration) (err error) {
+	defer m.opt.observe("hset", key, time.Now(), &err)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hash, err := memValue[memHash](m, "hset", key)
+	if err != nil {
+		return err
+	}
+
+	if hash == nil {
+		hash = make(memHash, len(values))
+		m.create(key, hash, ttl)
+	}
+
+	maps.Copy(hash, values)
+	return nil
+}
+
+func (m *memCache) HDel(key string, fields ...string) (err error) {
+	defer m.opt.observe("hdel", key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hash, err := memValue[memHash](m, "hdel", key)
+	if err != nil || hash == nil {
+		return err
+	}
+
+	for _, field := range fields {
+		delete(hash, field)
+	}
+
+	if len(hash) == 0 {
+		delete(m.data, key)
+	}
+	return nil
+}
+
+func (m *memCache) HGetAll(key string) (_ map[string]any, err error) {
+	defer m.opt.observe("hgetall", key, time.Now(), &err)
+
+	var values map[string]any
+	err = memRead(m, "hgetall", key, func(hash memHash) {
+		values = make(map[string]any, len(hash))
+		maps.Copy(values, hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func (m *memCache) HIncrBy(key, field string, value int64) (_ int64, err error) {
+	defer m.opt.observe("hincrby", key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hash, err := memValue[memHash](m, "hincrby", key)
+	if err != nil {
+		return 0, err
+	}
+
+	if hash == nil {
+		hash = make(memHash)
+		m.create(key, hash, nil)
+	}
+
+	var num int64
+	if current, ok := hash[field]; ok {
+		num, err = cast.NewCaster(current).Int64()
+		if err != nil {
+			return 0, &OpError{Op: "hincrby", Key: key, Err: ErrNotNumeric}
+		}
+	}
+
+	hash[field] = num + value
+	return num + value, nil
+}
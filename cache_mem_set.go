@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// memSet is the value of a set stored in the memory cache.
+type memSet map[string]struct{}
+
+// memSortedSet is the value of a sorted set stored in the memory cache,
+// mapping members to their scores.
+type memSortedSet map[string]float64
+
+func (m *memCache) SAdd(key string, members []string, ttl *time.Duration) (err error) {
+	defer m.opt.observe("sadd", key, time.Now(), &err)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	set, err := memValue[memSet](m, "sadd", key)
+	if err != nil {
+		return err
+	}
+
+	if set == nil {
+		set = make(memSet, len(members))
+		m.create(key, set, ttl)
+	}
+
+	for _, member := range members {
+		set[member] = struct{}{}
+	}
+	return nil
+}
+
+func (m *memCache) SRem(key string, members ...string) (err error) {
+	defer m.opt.observe("srem", key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	set, err := memValue[memSet](m, "srem", key)
+	if err != nil || set == nil {
+		return err
+	}
+
+	for _, member := range members {
+		delete(set, member)
+	}
+
+	if len(set) == 0 {
+		delete(m.data, key)
+	}
+	return nil
+}
+
+func (m *memCache) SIsMember(key, member string) (_ bool, err error) {
+	defer m.opt.observe("sismember", key, time.Now(), &err)
+
+	var ok bool
+	err = memRead(m, "sismember", key, func(set memSet) {
+		_, ok = set[member]
+	})
+	return ok, err
+}
+
+func (m *memCache) SMembers(key string) (_ []string, err error) {
+	defer m.opt.observe("smembers", key, time.Now(), &err)
+
+	var members []string
+	err = memRead(m, "smembers", key, func(set memSet) {
+		members = make([]string, 0, len(set))
+		for member := range set {
+			members = append(members, member)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (m *memCache) SCard(key string) (_ int64, err error) {
+	defer m.opt.observe("scard", key, time.Now(), &err)
+
+	var n int64
+	err = memRead(m, "scard", key, func(set memSet) {
+		n = int64(len(set))
+	})
+	return n, err
+}
+
+func (m *memCache) ZAdd(key string, members map[string]float64, ttl *time.Duration) (err error) {
+	defer m.opt.observe("zadd", key, time.Now(), &err)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	set, err := memValue[memSortedSet](m, "zadd", key)
+	if err != nil {
+		return err
+	}
+
+	if set == nil {
+		set = make(memSortedSet, len(members))
+		m.create(key, set, ttl)
+	}
+
+	maps.Copy(set, members)
+	return nil
+}
+
+func (m *memCache) ZIncrBy(key, member string, value float64) (_ float64, err error) {
+	defer m.opt.observe("zincrby", key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	set, err := memValue[memSortedSet](m, "zincrby", key)
+	if err != nil {
+		return 0, err
+	}
+
+	if set == nil {
+		set = make(memSortedSet)
+		m.create(key, set, nil)
+	}
+
+	set[member] += value
+	return set[member], nil
+}
+
+func (m *memCache) ZRange(key string, start, stop int64) (_ []ScoredMember, err error) {
+	defer m.opt.observe("zrange", key, time.Now(), &err)
+
+	var members []ScoredMember
+	err = memRead(m, "zrange", key, func(set memSortedSet) {
+		members = set.sorted()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(len(members))
+	if start < 0 {
+		start = max(size+start, 0)
+	}
+	if stop < 0 {
+		stop = size + stop
+	}
+	stop = min(stop, size-1)
+
+	if start > stop {
+		return []ScoredMember{}, nil
+	}
+	return members[start : stop+1], nil
+}
+
+func (m *memCache) ZRangeByScore(key string, min, max float64) (_ []ScoredMember, err error) {
+	defer m.opt.observe("zrangebyscore", key, time.Now(), &err)
+
+	var members []ScoredMember
+	err = memRead(m, "zrangebyscore", key, func(set memSortedSet) {
+		members = set.sorted()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(members, func(member ScoredMember) bool {
+		return member.Score < min || member.Score > max
+	}), nil
+}
+
+func (m *memCache) ZRem(key string, members ...string) (err error) {
+	defer m.opt.observe("zrem", key, time.Now(), &err)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	set, err := memValue[memSortedSet](m, "zrem", key)
+	if err != nil || set == nil {
+		return err
+	}
+
+	for _, member := range members {
+		delete(set, member)
+	}
+
+	if len(set) == 0 {
+		delete(m.data, key)
+	}
+	return nil
+}
+
+// sorted returns the members ordered by score, then lexicographically.
+func (s memSortedSet) sorted() []ScoredMember {
+	members := make([]ScoredMember, 0, len(s))
+	for member, score := range s {
+		members = append(members, ScoredMember{Member: member, Score: score})
+	}
+
+	slices.SortFunc(members, func(a, b ScoredMember) int {
+		return cmp.Or(cmp.Compare(a.Score, b.Score), cmp.Compare(a.Member, b.Member))
+	})
+	return members
+}
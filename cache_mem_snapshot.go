@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is the version of the memory cache snapshot format.
+const snapshotVersion = 1
+
+// Snapshotter is implemented by caches that can save and load their entries.
+// The memory cache returned by NewMemoryCache implements it.
+type Snapshotter interface {
+	// Snapshot writes all live entries to w.
+	Snapshot(w io.Writer) error
+
+	// Restore loads the entries written by Snapshot from r,
+	// overwriting existing keys and skipping entries expired since.
+	Restore(r io.Reader) error
+}
+
+// snapshotHeader starts a memory cache snapshot.
+type snapshotHeader struct {
+	Version int
+	Count   int
+}
+
+// snapshotRecord represents a single entry of a memory cache snapshot.
+// The expiry is absolute so that the remaining TTL is preserved across restarts.
+type snapshotRecord struct {
+	Key    string
+	Data   any
+	Expiry *time.Time
+}
+
+// Snapshot writes all live entries to w using encoding/gob.
+// Custom value types must be registered with gob.Register.
+func (m *memCache) Snapshot(w io.Writer) (err error) {
+	defer m.opt.observe("snapshot", "", time.Now(), &err)
+
+	now := m.opt.clock.Now()
+	m.mutex.RLock()
+	records := make([]snapshotRecord, 0, len(m.data))
+	for key, record := range m.data {
+		if record.expiry != nil && record.expiry.Before(now) {
+			continue
+		}
+
+		records = append(records, snapshotRecord{
+			Key:    key,
+			Data:   snapshotData(record.data),
+			Expiry: record.expiry,
+		})
+	}
+	m.mutex.RUnlock()
+
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(snapshotHeader{Version: snapshotVersion, Count: len(records)}); err != nil {
+		return fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("%w: %w", ErrCodec, err)
+		}
+	}
+
+	return nil
+}
+
+// snapshotData copies the data structures modified in place, so that they can be
+// encoded after the lock is released. The caller must hold the read lock.
+func snapshotData(data any) any {
+	switch data := data.(type) {
+	case memHash:
+		return maps.Clone(data)
+	case memSet:
+		return maps.Clone(data)
+	case memSortedSet:
+		return maps.Clone(data)
+	default:
+		return data
+	}
+}
+
+// Restore loads a snapshot written by Snapshot from r.
+// Nothing is loaded if the snapshot is invalid.
+func (m *memCache) Restore(r io.Reader) (err error) {
+	defer m.opt.observe("restore", "", time.Now(), &err)
+
+	decoder := gob.NewDecoder(r)
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("%w: unsupported snapshot version %d", ErrCodec, header.Version)
+	}
+
+	if header.Count < 0 {
+		return fmt.Errorf("%w: invalid snapshot entry count %d", ErrCodec, header.Count)
+	}
+
+	// Records are decoded one at a time as the count cannot be trusted for allocation
+	now := m.opt.clock.Now()
+	records := make(map[string]memRecord)
+	for range header.Count {
+		var record snapshotRecord
+		if err := decoder.Decode(&record); err != nil {
+			return fmt.Errorf("%w: %w", ErrCodec, err)
+		}
+
+		if record.Expiry != nil && record.Expiry.Before(now) {
+			continue
+		}
+
+		records[record.Key] = memRecord{
+			data:   record.Data,
+			expiry: record.Expiry,
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	maps.Copy(m.data, records)
+	return nil
+}
+
+// loadSnapshot restores the snapshot file if it exists.
+func (m *memCache) loadSnapshot() {
+	file, err := os.Open(m.opt.snapshotPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+
+	if err == nil {
+		defer file.Close()
+		err = m.Restore(file)
+	}
+
+	if err != nil {
+		m.opt.logger.Error(
+			"cache snapshot load failed",
+			slog.String("path", m.opt.snapshotPath),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// saveSnapshot writes a snapshot to the snapshot file atomically.
+func (m *memCache) saveSnapshot() error {
+	path := m.opt.snapshotPath
+	file, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := m.Snapshot(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// snapshotTick saves the snapshot file, logging failures.
+func (m *memCache) snapshotTick() {
+	if err := m.saveSnapshot(); err != nil {
+		m.opt.logger.Error(
+			"cache snapshot save failed",
+			slog.String("path", m.opt.snapshotPath),
+			slog.Any("error", err),
+		)
+	}
+}
@@ -0,0 +1,109 @@
+package cache_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCacheSnapshot(t *testing.T) {
+	t.Run("Snapshot and Restore", func(t *testing.T) {
+		source := cache.NewMemoryCache()
+		ttl := time.Hour
+		expired := time.Millisecond
+		require.NoError(t, source.Put("persistent", "value", nil))
+		require.NoError(t, source.Put("expiring", 42, &ttl))
+		require.NoError(t, source.Put("expired", "gone", &expired))
+		time.Sleep(5 * time.Millisecond)
+
+		var buf bytes.Buffer
+		require.NoError(t, source.(cache.Snapshotter).Snapshot(&buf))
+
+		target := cache.NewMemoryCache()
+		require.NoError(t, target.(cache.Snapshotter).Restore(&buf))
+
+		val, err := target.Get("persistent")
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+
+		val, err = target.Get("expiring")
+		require.NoError(t, err)
+		assert.Equal(t, 42, val)
+
+		remaining, err := target.TTL("expiring")
+		require.NoError(t, err)
+		assert.Greater(t, remaining, 59*time.Minute)
+		assert.LessOrEqual(t, remaining, ttl)
+
+		exists, err := target.Exists("expired")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Invalid snapshot", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		err := c.(cache.Snapshotter).Restore(bytes.NewBufferString("invalid"))
+		assert.ErrorIs(t, err, cache.ErrCodec)
+
+		for _, count := range []int{-1, 1 << 62} {
+			var buf bytes.Buffer
+			header := struct{ Version, Count int }{Version: 1, Count: count}
+			require.NoError(t, gob.NewEncoder(&buf).Encode(header))
+
+			err := c.(cache.Snapshotter).Restore(&buf)
+			assert.ErrorIs(t, err, cache.ErrCodec, count)
+		}
+	})
+
+	t.Run("Corrupt snapshot file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(struct{ Version, Count int }{Version: 1, Count: -1}))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+		// A corrupt snapshot is logged and the cache starts empty
+		c := cache.NewMemoryCache(cache.WithSnapshotFile(path, 0))
+		defer c.(io.Closer).Close()
+
+		exists, err := c.Exists("key")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Snapshot file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+		first := cache.NewMemoryCache(cache.WithSnapshotFile(path, 10*time.Millisecond))
+		require.NoError(t, first.Put("key", "value", nil))
+		require.NoError(t, first.(io.Closer).Close())
+
+		second := cache.NewMemoryCache(cache.WithSnapshotFile(path, 0))
+		defer second.(io.Closer).Close()
+
+		val, err := second.Get("key")
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+	})
+
+	t.Run("Periodic snapshot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+		first := cache.NewMemoryCache(cache.WithSnapshotFile(path, 10*time.Millisecond))
+		defer first.(io.Closer).Close()
+		require.NoError(t, first.Put("key", "value", nil))
+
+		assert.Eventually(t, func() bool {
+			second := cache.NewMemoryCache(cache.WithSnapshotFile(path, 0))
+			ok, _ := second.Exists("key")
+			return ok
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,410 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+const (
+	// memcachedTimeout is the dial and I/O deadline of memcached commands.
+	memcachedTimeout = 5 * time.Second
+
+	// memcachedMaxKey is the maximum length of memcached keys.
+	memcachedMaxKey = 250
+
+	// memcachedCASRetries is the number of attempts of a compare-and-swap
+	// before giving up on a key modified concurrently.
+	memcachedCASRetries = 100
+)
+
+// memcachedReplyError is an error reply sent by the memcached server.
+type memcachedReplyError string
+
+func (e memcachedReplyError) Error() string {
+	return "memcached: " + string(e)
+}
+
+// memcachedResponse represents a parsed meta command response.
+type memcachedResponse struct {
+	status string
+	flags  map[byte]string
+	value  []byte
+}
+
+// memcachedCache is a memcached-based implementation of the Cache interface
+// using the meta text protocol. Values are stored as text and returned as strings.
+type memcachedCache struct {
+	prefix string
+	addr   string
+	opt    option
+
+	mutex  sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewMemcachedCache creates a new memcached cache instance with a given prefix and server address.
+// The server must support the meta protocol (memcached 1.6 or later).
+// Counters are unsigned, decrementing below zero leaves the value at zero.
+func NewMemcachedCache(prefix, addr string, opts ...Option) Cache {
+	return &memcachedCache{
+		prefix: prefix,
+		addr:   addr,
+		opt:    newOption(opts...),
+	}
+}
+
+func (m *memcachedCache) Put(key string, value any, ttl *time.Duration) (err error) {
+	defer m.finish("put", key, time.Now(), &err)
+
+	data, err := formatValue(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.do(data, "ms", m.prefixer(key), strconv.Itoa(len(data)), "T"+memcachedTTL(safeValue(ttl, 0)))
+	return err
+}
+
+func (m *memcachedCache) Update(key string, value any) (_ bool, err error) {
+	defer m.finish("update", key, time.Now(), &err)
+
+	data, err := formatValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	return m.compareAndSwap(key, func(string) ([]byte, error) { return data, nil })
+}
+
+func (m *memcachedCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	ok, err := m.Update(key, value)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return m.Put(key, value, ttl)
+	}
+
+	return nil
+}
+
+func (m *memcachedCache) Get(key string) (_ any, err error) {
+	defer m.finish("get", key, time.Now(), &err)
+
+	val, _, err := m.get(key)
+	return val, err
+}
+
+func (m *memcachedCache) Lookup(key string) (_ any, _ bool, err error) {
+	defer m.finish("lookup", key, time.Now(), &err)
+
+	return m.get(key)
+}
+
+func (m *memcachedCache) Pull(key string) (any, error) {
+	val, err := m.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Forget(key); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (m *memcachedCache) Cast(key string) (cast.Caster, error) {
+	val, err := m.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cast.NewCaster(val), nil
+}
+
+func (m *memcachedCache) Exists(key string) (_ bool, err error) {
+	defer m.finish("exists", key, time.Now(), &err)
+
+	res, err := m.do(nil, "mg", m.prefixer(key))
+	if err != nil {
+		return false, err
+	}
+
+	return res.status == "HD", nil
+}
+
+func (m *memcachedCache) Forget(key string) (err error) {
+	defer m.finish("forget", key, time.Now(), &err)
+
+	_, err = m.do(nil, "md", m.prefixer(key))
+	return err
+}
+
+func (m *memcachedCache) TTL(key string) (_ time.Duration, err error) {
+	defer m.finish("ttl", key, time.Now(), &err)
+
+	res, err := m.do(nil, "mg", m.prefixer(key), "t")
+	if err != nil || res.status != "HD" {
+		return 0, err
+	}
+
+	seconds, err := strconv.ParseInt(res.flags['t'], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if seconds < 0 {
+		return time.Duration(math.MaxInt64), nil
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func (m *memcachedCache) Increment(key string, value int64) (_ bool, err error) {
+	defer m.finish("increment", key, time.Now(), &err)
+
+	return m.arithmetic(key, value)
+}
+
+func (m *memcachedCache) Decrement(key string, value int64) (_ bool, err error) {
+	defer m.finish("decrement", key, time.Now(), &err)
+
+	return m.arithmetic(key, -value)
+}
+
+func (m *memcachedCache) IncrementFloat(key string, value float64) (_ bool, err error) {
+	defer m.finish("increment_float", key, time.Now(), &err)
+
+	return m.compareAndSwap(key, func(current string) ([]byte, error) {
+		num, err := strconv.ParseFloat(current, 64)
+		if err != nil {
+			return nil, ErrNotNumeric
+		}
+		return formatValue(num + value)
+	})
+}
+
+func (m *memcachedCache) DecrementFloat(key string, value float64) (_ bool, err error) {
+	defer m.finish("decrement_float", key, time.Now(), &err)
+
+	return m.compareAndSwap(key, func(current string) ([]byte, error) {
+		num, err := strconv.ParseFloat(current, 64)
+		if err != nil {
+			return nil, ErrNotNumeric
+		}
+		return formatValue(num - value)
+	})
+}
+
+// get retrieves a value without logging the operation.
+func (m *memcachedCache) get(key string) (any, bool, error) {
+	res, err := m.do(nil, "mg", m.prefixer(key), "v")
+	if err != nil || res.status != "VA" {
+		return nil, false, err
+	}
+
+	return string(res.value), true, nil
+}
+
+// arithmetic increments a counter, decrementing for negative deltas.
+func (m *memcachedCache) arithmetic(key string, delta int64) (bool, error) {
+	args := []string{"ma", m.prefixer(key)}
+	if delta < 0 {
+		args = append(args, "MD", "D"+strconv.FormatUint(-uint64(delta), 10))
+	} else {
+		args = append(args, "D"+strconv.FormatInt(delta, 10))
+	}
+
+	res, err := m.do(nil, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return res.status == "HD", nil
+}
+
+// compareAndSwap replaces the value of an existing key with the result of modify,
+// preserving its TTL and retrying while the key is concurrently modified.
+func (m *memcachedCache) compareAndSwap(key string, modify func(current string) ([]byte, error)) (bool, error) {
+	for range memcachedCASRetries {
+		res, err := m.do(nil, "mg", m.prefixer(key), "v", "c", "t")
+		if err != nil || res.status != "VA" {
+			return false, err
+		}
+
+		data, err := modify(string(res.value))
+		if err != nil {
+			return false, err
+		}
+
+		ttl := "0"
+		if seconds, _ := strconv.ParseInt(res.flags['t'], 10, 64); seconds >= 0 {
+			ttl = memcachedTTL(time.Duration(max(seconds, 1)) * time.Second)
+		}
+
+		res, err = m.do(
+			data,
+			"ms", m.prefixer(key), strconv.Itoa(len(data)),
+			"C"+res.flags['c'], "T"+ttl,
+		)
+		if err != nil {
+			return false, err
+		}
+
+		switch res.status {
+		case "HD":
+			return true, nil
+		case "NF":
+			return false, nil
+		case "EX":
+			continue
+		default:
+			return false, fmt.Errorf("memcached: unexpected response %q", res.status)
+		}
+	}
+
+	return false, fmt.Errorf("memcached: value modified concurrently %d times", memcachedCASRetries)
+}
+
+// do sends a meta command with optional data and reads its response.
+// The connection is dialed lazily and dropped after I/O failures.
+func (m *memcachedCache) do(data []byte, args ...string) (*memcachedResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn == nil {
+		conn, err := net.DialTimeout("tcp", m.addr, memcachedTimeout)
+		if err != nil {
+			return nil, err
+		}
+		m.conn = conn
+		m.reader = bufio.NewReader(conn)
+	}
+
+	res, err := m.roundTrip(data, args)
+	var reply memcachedReplyError
+	if err != nil && !errors.As(err, &reply) {
+		m.conn.Close()
+		m.conn = nil
+		m.reader = nil
+	}
+
+	return res, err
+}
+
+// roundTrip writes a command and parses the response. It must be called with the mutex held.
+func (m *memcachedCache) roundTrip(data []byte, args []string) (*memcachedResponse, error) {
+	if err := m.conn.SetDeadline(time.Now().Add(memcachedTimeout)); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(strings.Join(args, " "))
+	buf.WriteString("\r\n")
+	if data != nil {
+		buf.Write(data)
+		buf.WriteString("\r\n")
+	}
+
+	if _, err := m.conn.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	line, err := m.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("memcached: malformed response %q", line)
+	}
+
+	switch fields[0] {
+	case "ERROR", "CLIENT_ERROR", "SERVER_ERROR":
+		return nil, memcachedReplyError(strings.TrimSpace(line))
+	}
+
+	res := &memcachedResponse{
+		status: fields[0],
+		flags:  make(map[byte]string),
+	}
+
+	flags := fields[1:]
+	if res.status == "VA" {
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("memcached: malformed response %q", line)
+		}
+
+		size, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("memcached: malformed response %q", line)
+		}
+
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(m.reader, value); err != nil {
+			return nil, err
+		}
+		res.value = value[:size]
+		flags = fields[2:]
+	}
+
+	for _, flag := range flags {
+		res.flags[flag[0]] = flag[1:]
+	}
+
+	return res, nil
+}
+
+// finish maps the error of a completed operation and logs it.
+func (m *memcachedCache) finish(op, key string, start time.Time, err *error) {
+	if *err != nil && strings.Contains((*err).Error(), "non-numeric") {
+		*err = fmt.Errorf("%w: %w", ErrNotNumeric, *err)
+	}
+
+	*err = backendError(op, key, *err)
+	m.opt.observe(op, key, start, err)
+}
+
+// prefixer adds the prefix to a key to create a namespaced key.
+// Keys that memcached cannot store, longer than 250 bytes or containing
+// whitespace or control characters, are replaced by their hash.
+func (m *memcachedCache) prefixer(key string) string {
+	encoded := m.opt.keyEncoder.Encode(m.prefix, key)
+	if len(encoded) <= memcachedMaxKey && !strings.ContainsFunc(encoded, func(r rune) bool {
+		return r <= ' ' || r == 0x7f
+	}) {
+		return encoded
+	}
+
+	return joinKey(escapeKey(m.prefix), hashKey(key))
+}
+
+// memcachedTTL converts a TTL to memcached expiration time.
+// Zero means no expiry and TTLs over 30 days are sent as unix timestamps.
+func memcachedTTL(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "0"
+	}
+
+	seconds := int64(math.Ceil(ttl.Seconds()))
+	if seconds > 30*24*60*60 {
+		seconds = time.Now().Add(ttl).Unix()
+	}
+
+	return strconv.FormatInt(seconds, 10)
+}
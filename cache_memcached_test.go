@@ -0,0 +1,264 @@
+package cache_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemcachedCache(t *testing.T) {
+	server, addr := startMemcachedServer(t)
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		return cache.NewMemcachedCache(uniquePrefix("memcached"), addr)
+	})
+
+	t.Run("Update preserves TTL", func(t *testing.T) {
+		c := cache.NewMemcachedCache("test", addr)
+		ttl := time.Hour
+		err := c.Put("ttlUpdateKey", "value", &ttl)
+		require.NoError(t, err)
+
+		ok, err := c.Update("ttlUpdateKey", "newValue")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		remaining, err := c.TTL("ttlUpdateKey")
+		require.NoError(t, err)
+		assert.Greater(t, remaining, 59*time.Minute)
+		assert.LessOrEqual(t, remaining, ttl)
+	})
+
+	t.Run("Not numeric", func(t *testing.T) {
+		c := cache.NewMemcachedCache("test", addr)
+		err := c.Put("textKey", "text", nil)
+		require.NoError(t, err)
+
+		_, err = c.Increment("textKey", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+		_, err = c.IncrementFloat("textKey", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+	})
+
+	t.Run("Unexpected swap response", func(t *testing.T) {
+		c := cache.NewMemcachedCache(uniquePrefix("memcached"), addr)
+		require.NoError(t, c.Put("key", "value", nil))
+
+		for _, status := range []string{"NS", "EX"} {
+			server.setSwapReply(status)
+			_, err := c.Update("key", "updated")
+			assert.Error(t, err, status)
+		}
+
+		server.setSwapReply("")
+		ok, err := c.Update("key", "updated")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Backend unavailable", func(t *testing.T) {
+		c := cache.NewMemcachedCache("test", "127.0.0.1:1")
+		_, err := c.Get("key")
+		assert.ErrorIs(t, err, cache.ErrBackendUnavailable)
+	})
+}
+
+// memcachedItem represents a value stored by the memcached stand-in.
+type memcachedItem struct {
+	value  []byte
+	expiry time.Time
+	cas    uint64
+}
+
+// memcachedServer is an in-process stand-in implementing the subset
+// of the memcached meta protocol used by the driver.
+type memcachedServer struct {
+	mutex sync.Mutex
+	items map[string]*memcachedItem
+	cas   uint64
+	swap  string
+}
+
+// startMemcachedServer starts a memcached stand-in and returns it with its address.
+func startMemcachedServer(t *testing.T) (*memcachedServer, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &memcachedServer{items: make(map[string]*memcachedItem)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server, listener.Addr().String()
+}
+
+// setSwapReply makes compare-and-swap writes fail with status, or succeed if empty.
+func (s *memcachedServer) setSwapReply(status string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.swap = status
+}
+
+func (s *memcachedServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			fmt.Fprint(conn, "ERROR\r\n")
+			continue
+		}
+
+		var data []byte
+		if fields[0] == "ms" {
+			size, _ := strconv.Atoi(fields[2])
+			data = make([]byte, size+2)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return
+			}
+			data = data[:size]
+			fields = append(fields[:2], fields[3:]...)
+		}
+
+		flags := make(map[byte]string)
+		var order []byte
+		for _, flag := range fields[2:] {
+			flags[flag[0]] = flag[1:]
+			order = append(order, flag[0])
+		}
+
+		fmt.Fprint(conn, s.handle(fields[0], fields[1], flags, order, data))
+	}
+}
+
+func (s *memcachedServer) handle(cmd, key string, flags map[byte]string, order []byte, data []byte) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	item, ok := s.items[key]
+	if ok && !item.expiry.IsZero() && !time.Now().Before(item.expiry) {
+		delete(s.items, key)
+		item, ok = nil, false
+	}
+
+	switch cmd {
+	case "ms":
+		if cas, has := flags['C']; has {
+			if s.swap != "" {
+				return s.swap + "\r\n"
+			}
+			if !ok {
+				return "NF\r\n"
+			}
+			if strconv.FormatUint(item.cas, 10) != cas {
+				return "EX\r\n"
+			}
+		}
+
+		s.cas++
+		s.items[key] = &memcachedItem{
+			value:  data,
+			expiry: memcachedExpiry(flags['T']),
+			cas:    s.cas,
+		}
+		return "HD\r\n"
+
+	case "mg":
+		if !ok {
+			return "EN\r\n"
+		}
+
+		var ret []string
+		for _, flag := range order {
+			switch flag {
+			case 't':
+				ttl := int64(-1)
+				if !item.expiry.IsZero() {
+					ttl = int64(time.Until(item.expiry).Seconds())
+				}
+				ret = append(ret, "t"+strconv.FormatInt(ttl, 10))
+			case 'c':
+				ret = append(ret, "c"+strconv.FormatUint(item.cas, 10))
+			}
+		}
+
+		suffix := ""
+		if len(ret) > 0 {
+			suffix = " " + strings.Join(ret, " ")
+		}
+
+		if _, has := flags['v']; has {
+			return fmt.Sprintf("VA %d%s\r\n%s\r\n", len(item.value), suffix, item.value)
+		}
+		return "HD" + suffix + "\r\n"
+
+	case "md":
+		if !ok {
+			return "NF\r\n"
+		}
+		delete(s.items, key)
+		return "HD\r\n"
+
+	case "ma":
+		if !ok {
+			return "NF\r\n"
+		}
+
+		num, err := strconv.ParseUint(string(item.value), 10, 64)
+		if err != nil {
+			return "CLIENT_ERROR cannot increment or decrement non-numeric value\r\n"
+		}
+
+		delta, _ := strconv.ParseUint(flags['D'], 10, 64)
+		if flags['M'] == "D" {
+			num -= min(delta, num)
+		} else {
+			num += delta
+		}
+
+		s.cas++
+		item.value = strconv.AppendUint(nil, num, 10)
+		item.cas = s.cas
+		return "HD\r\n"
+	}
+
+	return "ERROR\r\n"
+}
+
+// memcachedExpiry converts a memcached expiration time to an absolute time.
+func memcachedExpiry(value string) time.Time {
+	seconds, _ := strconv.ParseInt(value, 10, 64)
+	switch {
+	case seconds <= 0:
+		return time.Time{}
+	case seconds > 30*24*60*60:
+		return time.Unix(seconds, 0)
+	default:
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+}
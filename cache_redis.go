@@ -3,27 +3,50 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-universal/cast"
 	"github.com/redis/go-redis/v9"
 )
 
+// redisCreateScript runs a write command and sets the TTL only if the command created the key.
+var redisCreateScript = redis.NewScript(`
+local created = redis.call("EXISTS", KEYS[1]) == 0
+redis.call(ARGV[2], KEYS[1], unpack(ARGV, 3))
+if created and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// errRangePrefix is returned by Range on Redis caches without a prefix.
+var errRangePrefix = errors.New("ranging requires a key prefix")
+
 // redisCache is a Redis-based implementation of the Cache interface.
 type redisCache struct {
 	prefix string
-	client *redis.Client
+	client redis.UniversalClient
+	opt    option
 }
 
 // NewRedisCache creates a new Redis cache instance with a given prefix and Redis client.
-func NewRedisCache(prefix string, client *redis.Client) Cache {
+// The returned cache implements HashCache, SetCache and SortedSetCache.
+// Range, and so Export and Migrate, require a non-empty prefix: without one the
+// keys of the cache cannot be told apart from other keys of the database.
+func NewRedisCache(prefix string, client redis.UniversalClient, opts ...Option) Cache {
 	return &redisCache{
 		prefix: prefix,
 		client: client,
+		opt:    newOption(opts...),
 	}
 }
 
-func (r *redisCache) Put(key string, value any, ttl *time.Duration) error {
+func (r *redisCache) Put(key string, value any, ttl *time.Duration) (err error) {
+	defer r.finish("put", key, time.Now(), &err)
+
 	return r.client.Set(
 		context.Background(),
 		r.prefixer(key),
@@ -32,8 +55,10 @@ func (r *redisCache) Put(key string, value any, ttl *time.Duration) error {
 	).Err()
 }
 
-func (r *redisCache) Update(key string, value any) (bool, error) {
-	exists, err := r.Exists(key)
+func (r *redisCache) Update(key string, value any) (_ bool, err error) {
+	defer r.finish("update", key, time.Now(), &err)
+
+	exists, err := r.exists(key)
 	if err != nil || !exists {
 		return false, err
 	}
@@ -60,19 +85,19 @@ func (r *redisCache) PutOrUpdate(key string, value any, ttl *time.Duration) erro
 	return nil
 }
 
-func (r *redisCache) Get(key string) (any, error) {
-	val, err := r.client.Get(
-		context.TODO(),
-		r.prefixer(key),
-	).Result()
-
-	if errors.Is(err, redis.Nil) {
-		return nil, nil
-	}
+func (r *redisCache) Get(key string) (_ any, err error) {
+	defer r.finish("get", key, time.Now(), &err)
 
+	val, _, err := r.get(key)
 	return val, err
 }
 
+func (r *redisCache) Lookup(key string) (_ any, _ bool, err error) {
+	defer r.finish("lookup", key, time.Now(), &err)
+
+	return r.get(key)
+}
+
 func (r *redisCache) Pull(key string) (any, error) {
 	val, err := r.Get(key)
 	if err != nil {
@@ -95,21 +120,16 @@ func (r *redisCache) Cast(key string) (cast.Caster, error) {
 	return cast.NewCaster(val), nil
 }
 
-func (r *redisCache) Exists(key string) (bool, error) {
-	exists, err := r.client.Exists(
-		context.TODO(),
-		r.prefixer(key),
-	).Result()
+func (r *redisCache) Exists(key string) (_ bool, err error) {
+	defer r.finish("exists", key, time.Now(), &err)
 
-	if errors.Is(err, redis.Nil) {
-		return false, nil
-	}
-
-	return exists > 0, err
+	return r.exists(key)
 }
 
-func (r *redisCache) Forget(key string) error {
-	err := r.client.Del(
+func (r *redisCache) Forget(key string) (err error) {
+	defer r.finish("forget", key, time.Now(), &err)
+
+	err = r.client.Del(
 		context.TODO(),
 		r.prefixer(key),
 	).Err()
@@ -121,7 +141,9 @@ func (r *redisCache) Forget(key string) error {
 	return err
 }
 
-func (r *redisCache) TTL(key string) (time.Duration, error) {
+func (r *redisCache) TTL(key string) (_ time.Duration, err error) {
+	defer r.finish("ttl", key, time.Now(), &err)
+
 	ttl, err := r.client.TTL(
 		context.Background(),
 		r.prefixer(key),
@@ -134,8 +156,10 @@ func (r *redisCache) TTL(key string) (time.Duration, error) {
 	return ttl, err
 }
 
-func (r *redisCache) Increment(key string, value int64) (bool, error) {
-	exists, err := r.Exists(key)
+func (r *redisCache) Increment(key string, value int64) (_ bool, err error) {
+	defer r.finish("increment", key, time.Now(), &err)
+
+	exists, err := r.exists(key)
 	if err != nil || !exists {
 		return exists, err
 	}
@@ -148,8 +172,10 @@ func (r *redisCache) Increment(key string, value int64) (bool, error) {
 	return err == nil, err
 }
 
-func (r *redisCache) Decrement(key string, value int64) (bool, error) {
-	exists, err := r.Exists(key)
+func (r *redisCache) Decrement(key string, value int64) (_ bool, err error) {
+	defer r.finish("decrement", key, time.Now(), &err)
+
+	exists, err := r.exists(key)
 	if err != nil || !exists {
 		return exists, err
 	}
@@ -162,8 +188,10 @@ func (r *redisCache) Decrement(key string, value int64) (bool, error) {
 	return err == nil, err
 }
 
-func (r *redisCache) IncrementFloat(key string, value float64) (bool, error) {
-	exists, err := r.Exists(key)
+func (r *redisCache) IncrementFloat(key string, value float64) (_ bool, err error) {
+	defer r.finish("increment_float", key, time.Now(), &err)
+
+	exists, err := r.exists(key)
 	if err != nil || !exists {
 		return exists, err
 	}
@@ -176,8 +204,10 @@ func (r *redisCache) IncrementFloat(key string, value float64) (bool, error) {
 	return err == nil, err
 }
 
-func (r *redisCache) DecrementFloat(key string, value float64) (bool, error) {
-	exists, err := r.Exists(key)
+func (r *redisCache) DecrementFloat(key string, value float64) (_ bool, err error) {
+	defer r.finish("decrement_float", key, time.Now(), &err)
+
+	exists, err := r.exists(key)
 	if err != nil || !exists {
 		return exists, err
 	}
@@ -190,7 +220,113 @@ func (r *redisCache) DecrementFloat(key string, value float64) (bool, error) {
 	return err == nil, err
 }
 
+// Range calls fn for each string key under the prefix until fn returns false.
+// Keys hashed by the key encoder cannot be recovered and are skipped.
+// On clusters the keys of all masters are collected before fn is called.
+func (r *redisCache) Range(fn func(key string) bool) (err error) {
+	defer r.finish("range", "", time.Now(), &err)
+
+	if r.prefix == "" {
+		return errRangePrefix
+	}
+
+	// Keys hashed for length cannot be decoded and are reported once the others are ranged
+	var skipped atomic.Int64
+	prefix := r.prefixer("")
+	scan := func(ctx context.Context, client redis.UniversalClient, fn func(key string) bool) (bool, error) {
+		iter := client.ScanType(ctx, 0, prefix+"*", 100, "string").Iterator()
+		for iter.Next(ctx) {
+			key, ok := r.opt.keyEncoder.Decode(r.prefix, iter.Val())
+			if !ok {
+				skipped.Add(1)
+			} else if !fn(key) {
+				return true, nil
+			}
+		}
+		return false, iter.Err()
+	}
+
+	stopped := false
+	cluster, ok := r.client.(*redis.ClusterClient)
+	if !ok {
+		stopped, err = scan(context.Background(), r.client, fn)
+	} else {
+		var mutex sync.Mutex
+		var keys []string
+		err = cluster.ForEachMaster(context.Background(), func(ctx context.Context, node *redis.Client) error {
+			_, err := scan(ctx, node, func(key string) bool {
+				mutex.Lock()
+				keys = append(keys, key)
+				mutex.Unlock()
+				return true
+			})
+			return err
+		})
+
+		for _, key := range keys {
+			if err != nil || !fn(key) {
+				stopped = true
+				break
+			}
+		}
+	}
+
+	if err != nil || stopped || skipped.Load() == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w: %d keys skipped", ErrUndecodableKey, skipped.Load())
+}
+
+// get retrieves a value without logging the operation.
+func (r *redisCache) get(key string) (any, bool, error) {
+	val, err := r.client.Get(
+		context.TODO(),
+		r.prefixer(key),
+	).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+// exists checks whether a key exists without logging the operation.
+func (r *redisCache) exists(key string) (bool, error) {
+	exists, err := r.client.Exists(
+		context.TODO(),
+		r.prefixer(key),
+	).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+
+	return exists > 0, err
+}
+
+// create runs a write command of a data structure, applying the TTL if the key is created.
+func (r *redisCache) create(key string, ttl *time.Duration, command string, args ...any) error {
+	return redisCreateScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.prefixer(key)},
+		append([]any{safeValue(ttl, 0).Milliseconds(), command}, args...)...,
+	).Err()
+}
+
+// finish maps the error of a completed operation and logs it.
+func (r *redisCache) finish(op, key string, start time.Time, err *error) {
+	*err = redisError(op, key, *err)
+	r.opt.observe(op, key, start, err)
+}
+
 // prefixer adds the prefix to a key to create a namespaced key.
 func (r *redisCache) prefixer(key string) string {
-	return cacheKey(r.prefix, key)
+	return r.opt.keyEncoder.Encode(r.prefix, key)
 }
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+func (r *redisCache) HGet(key, field string) (_ any, err error) {
+	defer r.finish("hget", key, time.Now(), &err)
+
+	val, err := r.client.HGet(
+		context.Background(),
+		r.prefixer(key),
+		field,
+	).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (r *redisCache) HSet(key string, values map[string]any, ttl *time.Duration) (err error) {
+	defer r.finish("hset", key, time.Now(), &err)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, 2*len(values))
+	for field, value := range values {
+		args = append(args, field, value)
+	}
+
+	return r.create(key, ttl, "HSET", args...)
+}
+
+func (r *redisCache) HDel(key string, fields ...string) (err error) {
+	defer r.finish("hdel", key, time.Now(), &err)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return r.client.HDel(
+		context.Background(),
+		r.prefixer(key),
+		fields...,
+	).Err()
+}
+
+func (r *redisCache) HGetAll(key string) (_ map[string]any, err error) {
+	defer r.finish("hgetall", key, time.Now(), &err)
+
+	fields, err := r.client.HGetAll(
+		context.Background(),
+		r.prefixer(key),
+	).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(fields))
+	for field, value := range fields {
+		values[field] = value
+	}
+	return values, nil
+}
+
+func (r *redisCache) HIncrBy(key, field string, value int64) (_ int64, err error) {
+	defer r.finish("hincrby", key, time.Now(), &err)
+
+	return r.client.HIncrBy(
+		context.Background(),
+		r.prefixer(key),
+		field,
+		value,
+	).Result()
+}
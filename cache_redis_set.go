@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (r *redisCache) SAdd(key string, members []string, ttl *time.Duration) (err error) {
+	defer r.finish("sadd", key, time.Now(), &err)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+
+	return r.create(key, ttl, "SADD", args...)
+}
+
+func (r *redisCache) SRem(key string, members ...string) (err error) {
+	defer r.finish("srem", key, time.Now(), &err)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+
+	return r.client.SRem(
+		context.Background(),
+		r.prefixer(key),
+		args...,
+	).Err()
+}
+
+func (r *redisCache) SIsMember(key, member string) (_ bool, err error) {
+	defer r.finish("sismember", key, time.Now(), &err)
+
+	return r.client.SIsMember(
+		context.Background(),
+		r.prefixer(key),
+		member,
+	).Result()
+}
+
+func (r *redisCache) SMembers(key string) (_ []string, err error) {
+	defer r.finish("smembers", key, time.Now(), &err)
+
+	return r.client.SMembers(
+		context.Background(),
+		r.prefixer(key),
+	).Result()
+}
+
+func (r *redisCache) SCard(key string) (_ int64, err error) {
+	defer r.finish("scard", key, time.Now(), &err)
+
+	return r.client.SCard(
+		context.Background(),
+		r.prefixer(key),
+	).Result()
+}
+
+func (r *redisCache) ZAdd(key string, members map[string]float64, ttl *time.Duration) (err error) {
+	defer r.finish("zadd", key, time.Now(), &err)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, 2*len(members))
+	for member, score := range members {
+		args = append(args, score, member)
+	}
+
+	return r.create(key, ttl, "ZADD", args...)
+}
+
+func (r *redisCache) ZIncrBy(key, member string, value float64) (_ float64, err error) {
+	defer r.finish("zincrby", key, time.Now(), &err)
+
+	return r.client.ZIncrBy(
+		context.Background(),
+		r.prefixer(key),
+		value,
+		member,
+	).Result()
+}
+
+func (r *redisCache) ZRange(key string, start, stop int64) (_ []ScoredMember, err error) {
+	defer r.finish("zrange", key, time.Now(), &err)
+
+	members, err := r.client.ZRangeWithScores(
+		context.Background(),
+		r.prefixer(key),
+		start,
+		stop,
+	).Result()
+	return scoredMembers(members), err
+}
+
+func (r *redisCache) ZRangeByScore(key string, min, max float64) (_ []ScoredMember, err error) {
+	defer r.finish("zrangebyscore", key, time.Now(), &err)
+
+	members, err := r.client.ZRangeByScoreWithScores(
+		context.Background(),
+		r.prefixer(key),
+		&redis.ZRangeBy{Min: redisScore(min), Max: redisScore(max)},
+	).Result()
+	return scoredMembers(members), err
+}
+
+func (r *redisCache) ZRem(key string, members ...string) (err error) {
+	defer r.finish("zrem", key, time.Now(), &err)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+
+	return r.client.ZRem(
+		context.Background(),
+		r.prefixer(key),
+		args...,
+	).Err()
+}
+
+// scoredMembers converts go-redis sorted set members.
+func scoredMembers(members []redis.Z) []ScoredMember {
+	result := make([]ScoredMember, 0, len(members))
+	for _, member := range members {
+		result = append(result, ScoredMember{
+			Member: member.Member.(string),
+			Score:  member.Score,
+		})
+	}
+	return result
+}
+
+// redisScore formats a score bound of a sorted set range.
+func redisScore(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	}
+}
@@ -0,0 +1,299 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+// shardProbeKey is the key checked on each shard by Health.
+const shardProbeKey = "shard-health"
+
+// ShardedCache is a Cache that spreads keys over named shards.
+type ShardedCache interface {
+	Cache
+
+	// AddShard adds or replaces the shard with the given name.
+	// Only keys that hash to the new shard are remapped.
+	AddShard(name string, cache Cache)
+
+	// RemoveShard removes the named shard and reports whether it existed.
+	// Only keys owned by the removed shard are remapped.
+	RemoveShard(name string) bool
+
+	// Shards returns the sorted names of the shards.
+	Shards() []string
+
+	// Health probes every shard and returns its error, nil for healthy shards.
+	Health() map[string]error
+}
+
+// shard is a named cache of a sharded cache.
+type shard struct {
+	name  string
+	seed  uint64
+	cache Cache
+}
+
+// shardedCache is a client-side sharded implementation of the ShardedCache interface
+// routing keys with rendezvous (highest random weight) hashing.
+type shardedCache struct {
+	mutex  sync.RWMutex
+	shards []shard
+}
+
+// NewShardedCache creates a new sharded cache over the given named shards.
+// Each key is owned by a single shard chosen by rendezvous hashing, so that
+// adding or removing a shard only remaps the keys of that shard.
+// Shard names must be stable across processes sharing the same backends.
+func NewShardedCache(shards map[string]Cache) ShardedCache {
+	s := &shardedCache{}
+	for name, cache := range shards {
+		s.AddShard(name, cache)
+	}
+	return s
+}
+
+func (s *shardedCache) AddShard(name string, cache Cache) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i, found := slices.BinarySearchFunc(s.shards, name, func(sh shard, name string) int {
+		return strings.Compare(sh.name, name)
+	})
+	if found {
+		s.shards[i].cache = cache
+		return
+	}
+
+	s.shards = slices.Insert(s.shards, i, shard{
+		name:  name,
+		seed:  seededHash(0, name),
+		cache: cache,
+	})
+}
+
+func (s *shardedCache) RemoveShard(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i, found := slices.BinarySearchFunc(s.shards, name, func(sh shard, name string) int {
+		return strings.Compare(sh.name, name)
+	})
+	if found {
+		s.shards = slices.Delete(s.shards, i, i+1)
+	}
+
+	return found
+}
+
+func (s *shardedCache) Shards() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	names := make([]string, len(s.shards))
+	for i, sh := range s.shards {
+		names[i] = sh.name
+	}
+	return names
+}
+
+func (s *shardedCache) Health() map[string]error {
+	s.mutex.RLock()
+	shards := slices.Clone(s.shards)
+	s.mutex.RUnlock()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	health := make(map[string]error, len(shards))
+	for _, sh := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := sh.cache.Exists(shardProbeKey)
+			mutex.Lock()
+			health[sh.name] = err
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return health
+}
+
+func (s *shardedCache) Put(key string, value any, ttl *time.Duration) error {
+	c, err := s.shard("put", key)
+	if err != nil {
+		return err
+	}
+
+	return c.Put(key, value, ttl)
+}
+
+func (s *shardedCache) Update(key string, value any) (bool, error) {
+	c, err := s.shard("update", key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Update(key, value)
+}
+
+func (s *shardedCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	c, err := s.shard("put_or_update", key)
+	if err != nil {
+		return err
+	}
+
+	return c.PutOrUpdate(key, value, ttl)
+}
+
+func (s *shardedCache) Get(key string) (any, error) {
+	c, err := s.shard("get", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Get(key)
+}
+
+func (s *shardedCache) Lookup(key string) (any, bool, error) {
+	c, err := s.shard("lookup", key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return Lookup(c, key)
+}
+
+func (s *shardedCache) Pull(key string) (any, error) {
+	c, err := s.shard("pull", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Pull(key)
+}
+
+func (s *shardedCache) Cast(key string) (cast.Caster, error) {
+	c, err := s.shard("cast", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Cast(key)
+}
+
+func (s *shardedCache) Exists(key string) (bool, error) {
+	c, err := s.shard("exists", key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Exists(key)
+}
+
+func (s *shardedCache) Forget(key string) error {
+	c, err := s.shard("forget", key)
+	if err != nil {
+		return err
+	}
+
+	return c.Forget(key)
+}
+
+func (s *shardedCache) TTL(key string) (time.Duration, error) {
+	c, err := s.shard("ttl", key)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.TTL(key)
+}
+
+func (s *shardedCache) Increment(key string, value int64) (bool, error) {
+	c, err := s.shard("increment", key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Increment(key, value)
+}
+
+func (s *shardedCache) Decrement(key string, value int64) (bool, error) {
+	c, err := s.shard("decrement", key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.Decrement(key, value)
+}
+
+func (s *shardedCache) IncrementFloat(key string, value float64) (bool, error) {
+	c, err := s.shard("increment_float", key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.IncrementFloat(key, value)
+}
+
+func (s *shardedCache) DecrementFloat(key string, value float64) (bool, error) {
+	c, err := s.shard("decrement_float", key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.DecrementFloat(key, value)
+}
+
+// Range calls fn for each live key of every shard until fn returns false.
+// All shards must implement Enumerable.
+func (s *shardedCache) Range(fn func(key string) bool) error {
+	s.mutex.RLock()
+	shards := slices.Clone(s.shards)
+	s.mutex.RUnlock()
+
+	stopped := false
+	for _, sh := range shards {
+		enumerable, ok := sh.cache.(Enumerable)
+		if !ok {
+			return fmt.Errorf("shard %q is not enumerable", sh.name)
+		}
+
+		err := enumerable.Range(func(key string) bool {
+			stopped = !fn(key)
+			return !stopped
+		})
+		if err != nil || stopped {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shard returns the cache owning key, the shard with the highest hash of key.
+func (s *shardedCache) shard(op, key string) (Cache, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.shards) == 0 {
+		return nil, &OpError{Op: op, Key: key, Err: ErrBackendUnavailable}
+	}
+
+	var owner Cache
+	var best uint64
+	for i, sh := range s.shards {
+		if weight := seededHash(sh.seed, key); i == 0 || weight > best {
+			owner, best = sh.cache, weight
+		}
+	}
+
+	return owner, nil
+}
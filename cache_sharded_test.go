@@ -0,0 +1,100 @@
+package cache_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedCache(t *testing.T) {
+	clock := cache.NewFakeClock(time.Now())
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		return cache.NewShardedCache(map[string]cache.Cache{
+			"a": cache.NewMemoryCache(cache.WithClock(clock)),
+			"b": cache.NewMemoryCache(cache.WithClock(clock)),
+			"c": cache.NewMemoryCache(cache.WithClock(clock)),
+		})
+	}, cachetest.WithAdvance(clock.Advance))
+
+	testTypedValues(t, cache.NewShardedCache(map[string]cache.Cache{
+		"a": cache.NewMemoryCache(),
+		"b": cache.NewMemoryCache(),
+	}))
+
+	t.Run("Distribution and remapping", func(t *testing.T) {
+		shards := map[string]cache.Cache{
+			"a": cache.NewMemoryCache(),
+			"b": cache.NewMemoryCache(),
+			"c": cache.NewMemoryCache(),
+		}
+		sharded := cache.NewShardedCache(shards)
+		assert.Equal(t, []string{"a", "b", "c"}, sharded.Shards())
+
+		owners := make(map[string]string)
+		for i := range 300 {
+			key := "key" + strconv.Itoa(i)
+			require.NoError(t, sharded.Put(key, i, nil))
+			for name, shard := range shards {
+				if ok, _ := shard.Exists(key); ok {
+					owners[key] = name
+				}
+			}
+		}
+
+		counts := make(map[string]int)
+		for _, name := range owners {
+			counts[name]++
+		}
+		for name := range shards {
+			assert.Greater(t, counts[name], 50, name)
+		}
+
+		d := cache.NewMemoryCache()
+		sharded.AddShard("d", d)
+		moved := 0
+		for key, owner := range owners {
+			val, err := sharded.Get(key)
+			require.NoError(t, err)
+			if val == nil {
+				moved++
+				ok, _ := d.Exists(key)
+				assert.False(t, ok)
+				continue
+			}
+			ok, _ := shards[owner].Exists(key)
+			assert.True(t, ok)
+		}
+		assert.Greater(t, moved, 0)
+		assert.Less(t, moved, 150)
+
+		assert.True(t, sharded.RemoveShard("d"))
+		assert.False(t, sharded.RemoveShard("d"))
+		for key := range owners {
+			val, err := sharded.Get(key)
+			require.NoError(t, err)
+			assert.NotNil(t, val)
+		}
+	})
+
+	t.Run("Health", func(t *testing.T) {
+		sharded := cache.NewShardedCache(map[string]cache.Cache{
+			"up":   cache.NewMemoryCache(),
+			"down": cache.NewMemcachedCache("test", "127.0.0.1:1"),
+		})
+
+		health := sharded.Health()
+		assert.NoError(t, health["up"])
+		assert.ErrorIs(t, health["down"], cache.ErrBackendUnavailable)
+	})
+
+	t.Run("No shards", func(t *testing.T) {
+		sharded := cache.NewShardedCache(nil)
+		_, err := sharded.Get("key")
+		assert.ErrorIs(t, err, cache.ErrBackendUnavailable)
+	})
+}
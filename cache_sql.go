@@ -0,0 +1,469 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+// SQLDialect describes the SQL flavor of a database engine used by the SQL cache.
+// Float arithmetic is done in Go, the dialects only describe integer arithmetic.
+type SQLDialect struct {
+	numbered   bool
+	isInteger  string
+	addInteger string
+}
+
+var (
+	// SQLiteDialect is the dialect of SQLite 3.24 or later.
+	SQLiteDialect = SQLDialect{
+		isInteger:  "cache_value = CAST(CAST(cache_value AS INTEGER) AS TEXT)",
+		addInteger: "CAST(CAST(cache_value AS INTEGER) + ? AS TEXT)",
+	}
+
+	// PostgresDialect is the dialect of PostgreSQL 9.5 or later.
+	PostgresDialect = SQLDialect{
+		numbered:   true,
+		isInteger:  `cache_value ~ '^-?[0-9]+$'`,
+		addInteger: "CAST(CAST(cache_value AS BIGINT) + ? AS TEXT)",
+	}
+)
+
+// sqlSwapRetries is the number of attempts of a compare-and-swap
+// before giving up on a key modified concurrently.
+const sqlSwapRetries = 100
+
+// rxTable matches valid SQL table names.
+var rxTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlQueries holds the statements of a SQL cache table.
+type sqlQueries struct {
+	schema    []string
+	put       string
+	update    string
+	get       string
+	exists    string
+	forget    string
+	ttl       string
+	increment string
+	swap      string
+	purge     string
+	keys      string
+}
+
+// sqlCache is a database/sql based implementation of the Cache interface.
+// Values are stored as text and returned as strings.
+type sqlCache struct {
+	db      *sql.DB
+	queries sqlQueries
+	opt     option
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewSQLCache creates a new SQL cache instance storing entries in table,
+// creating the table if it does not exist. The table schema is:
+//
+//	CREATE TABLE <table> (
+//		cache_key   TEXT PRIMARY KEY,
+//		cache_value TEXT NOT NULL,
+//		expires_at  BIGINT -- unix milliseconds, NULL for no expiry
+//	);
+//	CREATE INDEX <table>_expires_at ON <table> (expires_at);
+//
+// Expired rows are purged every minute unless configured with WithCleanupInterval.
+// The returned cache implements io.Closer to stop the purge.
+func NewSQLCache(db *sql.DB, dialect SQLDialect, table string, opts ...Option) (Cache, error) {
+	if !rxTable.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	s := &sqlCache{
+		db:      db,
+		queries: newSQLQueries(dialect, table),
+		opt:     newOption(opts...),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	for _, query := range s.queries.schema {
+		if _, err := db.ExecContext(context.Background(), query); err != nil {
+			return nil, err
+		}
+	}
+
+	if interval := safeValue(s.opt.cleanup, time.Minute); interval > 0 {
+		go s.purge(interval)
+	} else {
+		close(s.done)
+	}
+
+	return s, nil
+}
+
+// newSQLQueries builds the statements of a table for the given dialect.
+func newSQLQueries(dialect SQLDialect, table string) sqlQueries {
+	alive := "cache_key = ? AND (expires_at IS NULL OR expires_at > ?)"
+	rebind := func(query string) string {
+		if !dialect.numbered {
+			return query
+		}
+
+		// Replace placeholders outside of quoted literals
+		var sb strings.Builder
+		n, quoted := 0, false
+		for _, r := range query {
+			switch {
+			case r == '\'':
+				quoted = !quoted
+			case r == '?' && !quoted:
+				n++
+				sb.WriteString("$" + strconv.Itoa(n))
+				continue
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	}
+
+	return sqlQueries{
+		schema: []string{
+			"CREATE TABLE IF NOT EXISTS " + table + " (cache_key TEXT PRIMARY KEY, cache_value TEXT NOT NULL, expires_at BIGINT)",
+			"CREATE INDEX IF NOT EXISTS " + table + "_expires_at ON " + table + " (expires_at)",
+		},
+		put: rebind("INSERT INTO " + table + " (cache_key, cache_value, expires_at) VALUES (?, ?, ?) " +
+			"ON CONFLICT (cache_key) DO UPDATE SET cache_value = excluded.cache_value, expires_at = excluded.expires_at"),
+		update:    rebind("UPDATE " + table + " SET cache_value = ? WHERE " + alive),
+		get:       rebind("SELECT cache_value FROM " + table + " WHERE " + alive),
+		exists:    rebind("SELECT 1 FROM " + table + " WHERE " + alive),
+		forget:    rebind("DELETE FROM " + table + " WHERE cache_key = ?"),
+		ttl:       rebind("SELECT expires_at FROM " + table + " WHERE " + alive),
+		increment: rebind("UPDATE " + table + " SET cache_value = " + dialect.addInteger + " WHERE " + alive + " AND " + dialect.isInteger),
+		swap:      rebind("UPDATE " + table + " SET cache_value = ? WHERE cache_value = ? AND " + alive),
+		purge:     rebind("DELETE FROM " + table + " WHERE expires_at IS NOT NULL AND expires_at <= ?"),
+		keys:      rebind("SELECT cache_key FROM " + table + " WHERE expires_at IS NULL OR expires_at > ?"),
+	}
+}
+
+func (s *sqlCache) Put(key string, value any, ttl *time.Duration) (err error) {
+	defer s.finish("put", key, time.Now(), &err)
+
+	data, err := formatValue(value)
+	if err != nil {
+		return err
+	}
+
+	var expiry sql.NullInt64
+	if d := safeValue(ttl, 0); d > 0 {
+		expiry = sql.NullInt64{Int64: time.Now().Add(d).UnixMilli(), Valid: true}
+	}
+
+	_, err = s.db.ExecContext(context.Background(), s.queries.put, key, string(data), expiry)
+	return err
+}
+
+func (s *sqlCache) Update(key string, value any) (_ bool, err error) {
+	defer s.finish("update", key, time.Now(), &err)
+
+	data, err := formatValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	return s.exec(s.queries.update, string(data), key, time.Now().UnixMilli())
+}
+
+func (s *sqlCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	ok, err := s.Update(key, value)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return s.Put(key, value, ttl)
+	}
+
+	return nil
+}
+
+func (s *sqlCache) Get(key string) (_ any, err error) {
+	defer s.finish("get", key, time.Now(), &err)
+
+	val, _, err := s.get(key)
+	return val, err
+}
+
+func (s *sqlCache) Lookup(key string) (_ any, _ bool, err error) {
+	defer s.finish("lookup", key, time.Now(), &err)
+
+	return s.get(key)
+}
+
+func (s *sqlCache) Pull(key string) (any, error) {
+	val, err := s.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Forget(key); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (s *sqlCache) Cast(key string) (cast.Caster, error) {
+	val, err := s.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cast.NewCaster(val), nil
+}
+
+func (s *sqlCache) Exists(key string) (_ bool, err error) {
+	defer s.finish("exists", key, time.Now(), &err)
+
+	return s.exists(key)
+}
+
+func (s *sqlCache) Forget(key string) (err error) {
+	defer s.finish("forget", key, time.Now(), &err)
+
+	_, err = s.db.ExecContext(context.Background(), s.queries.forget, key)
+	return err
+}
+
+func (s *sqlCache) TTL(key string) (_ time.Duration, err error) {
+	defer s.finish("ttl", key, time.Now(), &err)
+
+	var expiry sql.NullInt64
+	err = s.db.QueryRowContext(
+		context.Background(),
+		s.queries.ttl,
+		key,
+		time.Now().UnixMilli(),
+	).Scan(&expiry)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !expiry.Valid {
+		return time.Duration(math.MaxInt64), nil
+	}
+
+	return time.Until(time.UnixMilli(expiry.Int64)), nil
+}
+
+func (s *sqlCache) Increment(key string, value int64) (_ bool, err error) {
+	defer s.finish("increment", key, time.Now(), &err)
+
+	return s.modify(s.queries.increment, key, value)
+}
+
+func (s *sqlCache) Decrement(key string, value int64) (_ bool, err error) {
+	defer s.finish("decrement", key, time.Now(), &err)
+
+	return s.modify(s.queries.increment, key, -value)
+}
+
+func (s *sqlCache) IncrementFloat(key string, value float64) (_ bool, err error) {
+	defer s.finish("increment_float", key, time.Now(), &err)
+
+	return s.compareAndSwap(key, value)
+}
+
+func (s *sqlCache) DecrementFloat(key string, value float64) (_ bool, err error) {
+	defer s.finish("decrement_float", key, time.Now(), &err)
+
+	return s.compareAndSwap(key, -value)
+}
+
+// Range calls fn for each live key until fn returns false.
+// The keys are collected before fn is called.
+func (s *sqlCache) Range(fn func(key string) bool) (err error) {
+	defer s.finish("range", "", time.Now(), &err)
+
+	rows, err := s.db.QueryContext(context.Background(), s.queries.keys, time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if !fn(key) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Close stops the background purge of expired rows.
+// The underlying database is left open.
+func (s *sqlCache) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+	return nil
+}
+
+// get retrieves a value without logging the operation.
+func (s *sqlCache) get(key string) (any, bool, error) {
+	var val string
+	err := s.db.QueryRowContext(
+		context.Background(),
+		s.queries.get,
+		key,
+		time.Now().UnixMilli(),
+	).Scan(&val)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+// exists checks whether a key exists without logging the operation.
+func (s *sqlCache) exists(key string) (bool, error) {
+	var one int
+	err := s.db.QueryRowContext(
+		context.Background(),
+		s.queries.exists,
+		key,
+		time.Now().UnixMilli(),
+	).Scan(&one)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// exec runs a statement and reports whether it affected any row.
+func (s *sqlCache) exec(query string, args ...any) (bool, error) {
+	res, err := s.db.ExecContext(context.Background(), query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// modify atomically adds delta to a numeric value in a single statement.
+// A live key left untouched holds a non-numeric value.
+func (s *sqlCache) modify(query, key string, delta any) (bool, error) {
+	ok, err := s.exec(query, delta, key, time.Now().UnixMilli())
+	if err != nil || ok {
+		return ok, err
+	}
+
+	exists, err := s.exists(key)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	return false, ErrNotNumeric
+}
+
+// compareAndSwap adds delta to a float value. The value is parsed and formatted
+// in Go to keep full precision, and written only if unchanged since it was read,
+// retrying while the key is concurrently modified.
+func (s *sqlCache) compareAndSwap(key string, delta float64) (bool, error) {
+	for range sqlSwapRetries {
+		val, exists, err := s.get(key)
+		if err != nil || !exists {
+			return false, err
+		}
+
+		current := val.(string)
+		num, err := strconv.ParseFloat(current, 64)
+		if err != nil {
+			return false, ErrNotNumeric
+		}
+
+		data, err := formatValue(num + delta)
+		if err != nil {
+			return false, err
+		}
+
+		ok, err := s.exec(s.queries.swap, string(data), current, key, time.Now().UnixMilli())
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, fmt.Errorf("sql: value modified concurrently %d times", sqlSwapRetries)
+}
+
+// purge removes expired rows periodically until the cache is closed.
+func (s *sqlCache) purge(interval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		res, err := s.db.ExecContext(context.Background(), s.queries.purge, time.Now().UnixMilli())
+		if err != nil {
+			s.opt.logger.Error("cache purge failed", slog.Any("error", err))
+			continue
+		}
+
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			s.opt.logger.Debug(
+				"cache entries evicted",
+				slog.Int64("count", n),
+				slog.String("reason", "expired"),
+			)
+		}
+	}
+}
+
+// finish maps the error of a completed operation and logs it.
+func (s *sqlCache) finish(op, key string, start time.Time, err *error) {
+	*err = backendError(op, key, *err)
+	s.opt.observe(op, key, start, err)
+}
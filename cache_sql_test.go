@@ -0,0 +1,201 @@
+package cache_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLCache(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "cache.db")+"?_pragma=busy_timeout(5000)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	var tables atomic.Int32
+	sqlCache, err := cache.NewSQLCache(db, cache.SQLiteDialect, "cache_entries")
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlCache.(io.Closer).Close() })
+
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		c, err := cache.NewSQLCache(db, cache.SQLiteDialect, "suite_"+strconv.Itoa(int(tables.Add(1))))
+		require.NoError(t, err)
+		t.Cleanup(func() { c.(io.Closer).Close() })
+		return c
+	})
+
+	t.Run("Not numeric", func(t *testing.T) {
+		err := sqlCache.Put("textKey", "text", nil)
+		require.NoError(t, err)
+
+		_, err = sqlCache.Increment("textKey", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+		_, err = sqlCache.IncrementFloat("textKey", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+	})
+
+	t.Run("Float values", func(t *testing.T) {
+		// SQLite arithmetic would round results to 15 significant digits
+		values := map[string]string{
+			"0.2":                 "0.30000000000000004",
+			"0.30000000000000004": "0.4",
+			"1e21":                "1000000000000000000000",
+			"1.50":                "1.6",
+			"123456789.123456789": "123456789.22345679",
+		}
+
+		for value, expected := range values {
+			require.NoError(t, sqlCache.Put("floatKey", value, nil))
+			ok, err := sqlCache.IncrementFloat("floatKey", 0.1)
+			require.NoError(t, err, value)
+			assert.True(t, ok, value)
+
+			got, err := sqlCache.Get("floatKey")
+			require.NoError(t, err)
+			assert.Equal(t, expected, got, value)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		var keys []string
+		err := sqlCache.(cache.Enumerable).Range(func(key string) bool {
+			keys = append(keys, key)
+			return true
+		})
+		require.NoError(t, err)
+		assert.Contains(t, keys, "textKey")
+	})
+
+	t.Run("Invalid table", func(t *testing.T) {
+		_, err := cache.NewSQLCache(db, cache.SQLiteDialect, "entries; DROP TABLE x")
+		assert.Error(t, err)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		purging, err := cache.NewSQLCache(
+			db, cache.SQLiteDialect, "purged_entries",
+			cache.WithCleanupInterval(10*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer purging.(io.Closer).Close()
+
+		ttl := time.Millisecond
+		err = purging.Put("expiringKey", "value", &ttl)
+		require.NoError(t, err)
+		err = purging.Put("persistentKey", "value", nil)
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			var count int
+			err := db.QueryRow("SELECT COUNT(*) FROM purged_entries").Scan(&count)
+			return err == nil && count == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestPostgresDialect(t *testing.T) {
+	recorder := &recordingConnector{}
+	db := sql.OpenDB(recorder)
+	t.Cleanup(func() { db.Close() })
+
+	c, err := cache.NewSQLCache(db, cache.PostgresDialect, "cache_entries", cache.WithCleanupInterval(0))
+	require.NoError(t, err)
+
+	ttl := time.Minute
+	require.NoError(t, c.Put("key", "value", &ttl))
+	_, err = c.Increment("key", 1)
+	require.NoError(t, err)
+	_, err = c.Get("key")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"CREATE TABLE IF NOT EXISTS cache_entries (cache_key TEXT PRIMARY KEY, cache_value TEXT NOT NULL, expires_at BIGINT)",
+		"CREATE INDEX IF NOT EXISTS cache_entries_expires_at ON cache_entries (expires_at)",
+		"INSERT INTO cache_entries (cache_key, cache_value, expires_at) VALUES ($1, $2, $3) " +
+			"ON CONFLICT (cache_key) DO UPDATE SET cache_value = excluded.cache_value, expires_at = excluded.expires_at",
+		"UPDATE cache_entries SET cache_value = CAST(CAST(cache_value AS BIGINT) + $1 AS TEXT) " +
+			"WHERE cache_key = $2 AND (expires_at IS NULL OR expires_at > $3) AND cache_value ~ '^-?[0-9]+$'",
+		"SELECT cache_value FROM cache_entries WHERE cache_key = $1 AND (expires_at IS NULL OR expires_at > $2)",
+	}, recorder.queries)
+}
+
+// recordingConnector is a database/sql connector recording the statements it runs.
+// Statements affect one row and queries return no rows.
+type recordingConnector struct {
+	mutex   sync.Mutex
+	queries []string
+}
+
+func (r *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return recordingConn{r}, nil
+}
+
+func (r *recordingConnector) Driver() driver.Driver {
+	return nil
+}
+
+type recordingConn struct {
+	connector *recordingConnector
+}
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	c.connector.mutex.Lock()
+	defer c.connector.mutex.Unlock()
+
+	c.connector.queries = append(c.connector.queries, query)
+	return recordingStmt{}, nil
+}
+
+func (c recordingConn) Close() error {
+	return nil
+}
+
+func (c recordingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type recordingStmt struct{}
+
+func (recordingStmt) Close() error {
+	return nil
+}
+
+func (recordingStmt) NumInput() int {
+	return -1
+}
+
+func (recordingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return recordingRows{}, nil
+}
+
+type recordingRows struct{}
+
+func (recordingRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (recordingRows) Close() error {
+	return nil
+}
+
+func (recordingRows) Next([]driver.Value) error {
+	return io.EOF
+}
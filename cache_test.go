@@ -1,253 +1,185 @@
 package cache_test
 
 import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestMemoryCache(t *testing.T) {
-	memCache := cache.NewMemoryCache()
+	clock := cache.NewFakeClock(time.Now())
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		return cache.NewMemoryCache(cache.WithClock(clock))
+	}, cachetest.WithAdvance(clock.Advance))
 
-	t.Run("Put and Get", func(t *testing.T) {
-		key := "testKey"
-		value := "testValue"
-		ttl := 5 * time.Second
+	testTypedValues(t, cache.NewMemoryCache())
 
-		err := memCache.Put(key, value, &ttl)
-		require.NoError(t, err)
-
-		retrievedValue, err := memCache.Get(key)
-		require.NoError(t, err)
-		assert.Equal(t, value, retrievedValue)
-	})
-
-	t.Run("Update existing key", func(t *testing.T) {
-		key := "testKey"
-		newValue := "newValue"
-
-		exists, err := memCache.Update(key, newValue)
-		require.NoError(t, err)
-		assert.True(t, exists)
-
-		retrievedValue, err := memCache.Get(key)
-		require.NoError(t, err)
-		assert.Equal(t, newValue, retrievedValue)
-	})
-
-	t.Run("PutOrUpdate with TTL", func(t *testing.T) {
-		key := "testKey"
-		value := "overriddenValue"
-		ttl := 10 * time.Second
-
-		err := memCache.PutOrUpdate(key, value, &ttl)
-		require.NoError(t, err)
-
-		retrievedValue, err := memCache.Get(key)
-		require.NoError(t, err)
-		assert.Equal(t, value, retrievedValue)
+	t.Run("Without Looker", func(t *testing.T) {
+		testTypedValues(t, plainCache{cache.NewMemoryCache()})
 	})
+}
 
-	t.Run("Exists", func(t *testing.T) {
-		key := "testKey"
-
-		exists, err := memCache.Exists(key)
-		require.NoError(t, err)
-		assert.True(t, exists)
-	})
-
-	t.Run("Forget", func(t *testing.T) {
-		key := "testKey"
-
-		err := memCache.Forget(key)
-		require.NoError(t, err)
+// plainCache hides the optional interfaces of a cache, such as Looker.
+type plainCache struct {
+	cache.Cache
+}
 
-		exists, err := memCache.Exists(key)
+func TestDiskCache(t *testing.T) {
+	clock := cache.NewFakeClock(time.Now())
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		diskCache, err := cache.NewDiskCache(t.TempDir(), cache.WithClock(clock))
 		require.NoError(t, err)
-		assert.False(t, exists)
-	})
+		t.Cleanup(func() { diskCache.(io.Closer).Close() })
+		return diskCache
+	}, cachetest.WithAdvance(clock.Advance))
 
-	t.Run("Increment and Decrement", func(t *testing.T) {
-		key := "counter"
-		initialValue := int64(10)
-		err := memCache.Put(key, initialValue, nil)
-		require.NoError(t, err)
+	diskCache, err := cache.NewDiskCache(t.TempDir())
+	require.NoError(t, err)
+	testTypedValues(t, diskCache)
 
-		incremented, err := memCache.Increment(key, 5)
+	t.Run("Persistence", func(t *testing.T) {
+		dir := t.TempDir()
+		first, err := cache.NewDiskCache(dir)
 		require.NoError(t, err)
-		assert.True(t, incremented)
 
-		value, err := memCache.Get(key)
+		err = first.Put("persistentKey", "persistentValue", nil)
 		require.NoError(t, err)
-		assert.Equal(t, int64(15), value)
 
-		decremented, err := memCache.Decrement(key, 3)
+		second, err := cache.NewDiskCache(dir)
 		require.NoError(t, err)
-		assert.True(t, decremented)
 
-		value, err = memCache.Get(key)
+		value, err := second.Get("persistentKey")
 		require.NoError(t, err)
-		assert.Equal(t, int64(12), value)
+		assert.Equal(t, "persistentValue", value)
 	})
 
-	t.Run("Increment and Decrement Float", func(t *testing.T) {
-		key := "counter"
-		initialValue := float64(10.3)
-		err := memCache.Put(key, initialValue, nil)
-		require.NoError(t, err)
-
-		incremented, err := memCache.IncrementFloat(key, 5)
+	t.Run("Expiry", func(t *testing.T) {
+		ttl := time.Millisecond
+		err := diskCache.Put("expiringKey", "value", &ttl)
 		require.NoError(t, err)
-		assert.True(t, incremented)
 
-		value, err := memCache.Get(key)
+		time.Sleep(5 * time.Millisecond)
+		exists, err := diskCache.Exists("expiringKey")
 		require.NoError(t, err)
-		assert.Equal(t, float64(15.3), value)
-
-		decremented, err := memCache.DecrementFloat(key, 3)
-		require.NoError(t, err)
-		assert.True(t, decremented)
-
-		value, err = memCache.Get(key)
-		require.NoError(t, err)
-		assert.Equal(t, float64(12.3), value)
+		assert.False(t, exists)
 	})
 
-	t.Run("TTL", func(t *testing.T) {
-		key := "ttlKey"
-		value := "ttlValue"
-		ttl := 2 * time.Second
-
-		err := memCache.Put(key, value, &ttl)
-		require.NoError(t, err)
-
-		retrievedTTL, err := memCache.TTL(key)
-		require.NoError(t, err)
-		assert.LessOrEqual(t, retrievedTTL.Seconds(), ttl.Seconds())
+	t.Run("Cleanup", func(t *testing.T) {
+		dir := t.TempDir()
+		c, err := cache.NewDiskCache(dir, cache.WithCleanupInterval(10*time.Millisecond))
+		require.NoError(t, err)
+		defer c.(io.Closer).Close()
+
+		ttl := time.Millisecond
+		require.NoError(t, c.Put("expiringKey", "value", &ttl))
+		require.NoError(t, c.Put("persistentKey", "value", nil))
+
+		// Expired files are removed without being read
+		assert.Eventually(t, func() bool {
+			files := 0
+			err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+				if err == nil && !entry.IsDir() {
+					files++
+				}
+				return err
+			})
+			return err == nil && files == 1
+		}, time.Second, 10*time.Millisecond)
+
+		value, err := c.Get("persistentKey")
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("Corrupt files", func(t *testing.T) {
+		dir := t.TempDir()
+		clock := cache.NewFakeClock(time.Now())
+		logs := &syncBuffer{}
+		c, err := cache.NewDiskCache(
+			dir,
+			cache.WithClock(clock),
+			cache.WithCleanupInterval(time.Minute),
+			cache.WithLogger(slog.New(slog.NewTextHandler(logs, nil))),
+		)
+		require.NoError(t, err)
+		defer c.(io.Closer).Close()
+
+		ttl := time.Second
+		require.NoError(t, c.Put("expiringKey", "value", &ttl))
+		require.NoError(t, c.Put("persistentKey", "value", nil))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt"), []byte("garbage"), 0o644))
+
+		// Undecodable files are skipped by Range and sweeps
+		var keys []string
+		require.NoError(t, c.(cache.Enumerable).Range(func(key string) bool {
+			keys = append(keys, key)
+			return true
+		}))
+		assert.ElementsMatch(t, []string{"expiringKey", "persistentKey"}, keys)
+		assert.Contains(t, logs.String(), "cache file skipped")
+
+		assert.Eventually(t, func() bool {
+			clock.Advance(time.Minute)
+			_, err := os.Stat(filepath.Join(dir, "corrupt"))
+			exists, _ := c.Exists("persistentKey")
+			return err == nil && exists && strings.Count(logs.String(), "cache file skipped") > 1
+		}, time.Second, 5*time.Millisecond)
 	})
 }
 
 func TestRedisCache(t *testing.T) {
-	redisCache := cache.NewRedisCache("test", redis.NewClient(&redis.Options{}))
-
-	t.Run("Put and Get", func(t *testing.T) {
-		key := "redisTestKey"
-		value := "redisTestValue"
-		ttl := 5 * time.Second
-
-		err := redisCache.Put(key, value, &ttl)
-		require.NoError(t, err)
-
-		retrievedValue, err := redisCache.Get(key)
-		require.NoError(t, err)
-		assert.Equal(t, value, retrievedValue)
-	})
-
-	t.Run("Update existing key", func(t *testing.T) {
-		key := "redisTestKey"
-		newValue := "redisNewValue"
-
-		exists, err := redisCache.Update(key, newValue)
-		require.NoError(t, err)
-		assert.True(t, exists)
-
-		retrievedValue, err := redisCache.Get(key)
-		require.NoError(t, err)
-		assert.Equal(t, newValue, retrievedValue)
-	})
+	client := redis.NewClient(&redis.Options{})
 
-	t.Run("PutOrUpdate with TTL", func(t *testing.T) {
-		key := "redisTestKey"
-		value := "redisOverriddenValue"
-		ttl := 10 * time.Second
-
-		err := redisCache.PutOrUpdate(key, value, &ttl)
-		require.NoError(t, err)
+	// The test server does not expire keys in real time
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		return cache.NewRedisCache(uniquePrefix("redis"), client)
+	}, cachetest.WithoutExpiry())
+}
 
-		retrievedValue, err := redisCache.Get(key)
+// testTypedValues checks that a cache returning values as stored keeps their types.
+func testTypedValues(t *testing.T, c cache.Cache) {
+	t.Run("Typed values", func(t *testing.T) {
+		err := c.Put("nilKey", nil, nil)
 		require.NoError(t, err)
-		assert.Equal(t, value, retrievedValue)
-	})
-
-	t.Run("Exists", func(t *testing.T) {
-		key := "redisTestKey"
 
-		exists, err := redisCache.Exists(key)
+		value, exists, err := cache.Lookup(c, "nilKey")
 		require.NoError(t, err)
 		assert.True(t, exists)
-	})
+		assert.Nil(t, value)
 
-	t.Run("Forget", func(t *testing.T) {
-		key := "redisTestKey"
-
-		err := redisCache.Forget(key)
+		err = c.Put("counter", int64(10), nil)
 		require.NoError(t, err)
-
-		exists, err := redisCache.Exists(key)
+		_, err = c.Increment("counter", 5)
 		require.NoError(t, err)
-		assert.False(t, exists)
-	})
 
-	t.Run("Increment and Decrement", func(t *testing.T) {
-		key := "redisCounter"
-		initialValue := int64(10)
-		err := redisCache.Put(key, initialValue, nil)
+		value, err = c.Get("counter")
 		require.NoError(t, err)
+		assert.Equal(t, int64(15), value)
 
-		incremented, err := redisCache.Increment(key, 5)
-		require.NoError(t, err)
-		assert.True(t, incremented)
-
-		value, err := redisCache.Cast(key)
-		require.NoError(t, err)
-		assert.Equal(t, int64(15), value.Int64Safe(0))
-
-		decremented, err := redisCache.Decrement(key, 3)
-		require.NoError(t, err)
-		assert.True(t, decremented)
-
-		value, err = redisCache.Cast(key)
-		require.NoError(t, err)
-		assert.Equal(t, int64(12), value.Int64Safe(0))
-	})
-
-	t.Run("Increment and Decrement Float", func(t *testing.T) {
-		key := "counter"
-		initialValue := float64(10.3)
-		err := redisCache.Put(key, initialValue, nil)
-		require.NoError(t, err)
-
-		incremented, err := redisCache.IncrementFloat(key, 5)
-		require.NoError(t, err)
-		assert.True(t, incremented)
-
-		value, err := redisCache.Cast(key)
+		err = c.Put("floatCounter", float64(10.3), nil)
 		require.NoError(t, err)
-		assert.Equal(t, float64(15.3), value.Float64Safe(0))
-
-		decremented, err := redisCache.DecrementFloat(key, 3)
+		_, err = c.IncrementFloat("floatCounter", 5)
 		require.NoError(t, err)
-		assert.True(t, decremented)
 
-		value, err = redisCache.Cast(key)
+		value, err = c.Get("floatCounter")
 		require.NoError(t, err)
-		assert.Equal(t, float64(12.3), value.Float64Safe(0))
+		assert.Equal(t, float64(15.3), value)
 	})
+}
 
-	t.Run("TTL", func(t *testing.T) {
-		key := "redisTTLKey"
-		value := "redisTTLValue"
-		ttl := 2 * time.Second
-
-		err := redisCache.Put(key, value, &ttl)
-		require.NoError(t, err)
-
-		retrievedTTL, err := redisCache.TTL(key)
-		require.NoError(t, err)
-		assert.LessOrEqual(t, retrievedTTL.Seconds(), ttl.Seconds())
-	})
+// uniquePrefix returns a key prefix not used by earlier runs against a shared server.
+func uniquePrefix(name string) string {
+	return name + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
 }
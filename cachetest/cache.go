@@ -0,0 +1,316 @@
+package cachetest
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunCacheSuite runs the conformance tests of the cache.Cache interface against
+// the caches created by newCache. Values are compared through cast.Caster, so
+// backends may return them as stored or as strings.
+func RunCacheSuite(t *testing.T, newCache CacheFactory, opts ...Option) {
+	cfg := newConfig(opts...)
+
+	t.Run("Put and Get", func(t *testing.T) {
+		c := newCache(t)
+		ttl := time.Minute
+
+		require.NoError(t, c.Put("key", "value", &ttl))
+		assertValue(t, c, "key", "value")
+
+		require.NoError(t, c.Put("key", "overwritten", nil))
+		assertValue(t, c, "key", "overwritten")
+
+		val, err := c.Get("missing")
+		require.NoError(t, err)
+		assert.Nil(t, val)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		c := newCache(t)
+		ttl := time.Minute
+
+		ok, err := c.Update("key", "value")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assertMissing(t, c, "key")
+
+		require.NoError(t, c.Put("key", "value", &ttl))
+		ok, err = c.Update("key", "updated")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assertValue(t, c, "key", "updated")
+		assertTTL(t, c, "key", ttl)
+	})
+
+	t.Run("PutOrUpdate", func(t *testing.T) {
+		c := newCache(t)
+		ttl := time.Minute
+
+		require.NoError(t, c.PutOrUpdate("key", "value", &ttl))
+		assertValue(t, c, "key", "value")
+		assertTTL(t, c, "key", ttl)
+
+		longer := time.Hour
+		require.NoError(t, c.PutOrUpdate("key", "updated", &longer))
+		assertValue(t, c, "key", "updated")
+		assertTTL(t, c, "key", ttl)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Put("empty", "", nil))
+		val, exists, err := cache.Lookup(c, "empty")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, "", cast.NewCaster(val).StringSafe("-"))
+
+		val, exists, err = cache.Lookup(c, "missing")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Nil(t, val)
+	})
+
+	t.Run("Pull", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Put("key", "value", nil))
+		val, err := c.Pull("key")
+		require.NoError(t, err)
+		assert.Equal(t, "value", cast.NewCaster(val).StringSafe(""))
+		assertMissing(t, c, "key")
+
+		val, err = c.Pull("missing")
+		require.NoError(t, err)
+		assert.Nil(t, val)
+	})
+
+	t.Run("Cast", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Put("key", 42, nil))
+		caster, err := c.Cast("key")
+		require.NoError(t, err)
+		assert.Equal(t, 42, caster.IntSafe(0))
+
+		caster, err = c.Cast("missing")
+		require.NoError(t, err)
+		assert.True(t, caster.IsNil())
+	})
+
+	t.Run("Exists and Forget", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Put("key", "value", nil))
+		exists, err := c.Exists("key")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		require.NoError(t, c.Forget("key"))
+		assertMissing(t, c, "key")
+
+		require.NoError(t, c.Forget("missing"))
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		c := newCache(t)
+		ttl := time.Minute
+
+		require.NoError(t, c.Put("key", "value", &ttl))
+		assertTTL(t, c, "key", ttl)
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		cfg.skipExpiry(t)
+		c := newCache(t)
+		ttl := time.Second
+		long := time.Hour
+
+		require.NoError(t, c.Put("short", "value", &ttl))
+		require.NoError(t, c.Put("long", "value", &long))
+		require.NoError(t, c.Put("forever", "value", nil))
+		cfg.advance(1500 * time.Millisecond)
+
+		assertMissing(t, c, "short")
+		val, exists, err := cache.Lookup(c, "short")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Nil(t, val)
+
+		ok, err := c.Update("short", "value")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		assertValue(t, c, "long", "value")
+		assertValue(t, c, "forever", "value")
+	})
+
+	t.Run("Counters", func(t *testing.T) {
+		c := newCache(t)
+
+		ok, err := c.Increment("missing", 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assertMissing(t, c, "missing")
+
+		require.NoError(t, c.Put("counter", 10, nil))
+		ok, err = c.Increment("counter", 5)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assertInt(t, c, "counter", 15)
+
+		ok, err = c.Decrement("counter", 3)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assertInt(t, c, "counter", 12)
+
+		require.NoError(t, c.Put("float", 10.5, nil))
+		ok, err = c.IncrementFloat("float", 5)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = c.DecrementFloat("float", 1.25)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		caster, err := c.Cast("float")
+		require.NoError(t, err)
+		assert.InDelta(t, 14.25, caster.Float64Safe(0), 1e-9)
+
+		ok, err = c.IncrementFloat("missing", 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Counters keep TTL", func(t *testing.T) {
+		c := newCache(t)
+		ttl := time.Minute
+
+		require.NoError(t, c.Put("counter", 1, &ttl))
+		_, err := c.Increment("counter", 1)
+		require.NoError(t, err)
+		assertTTL(t, c, "counter", ttl)
+	})
+
+	t.Run("Not numeric", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Put("text", "value", nil))
+		_, err := c.Increment("text", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+		var opErr *cache.OpError
+		require.ErrorAs(t, err, &opErr)
+		assert.Equal(t, "increment", opErr.Op)
+		assert.Equal(t, "text", opErr.Key)
+
+		_, err = c.IncrementFloat("text", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		c := newCache(t)
+		keys := []string{
+			"user.1", "user1", "user:1", "user 1", "user-1", "user_1",
+			"کاربر", "ключ", "emoji 🔑", "path/to/key", "a%20b",
+			strings.Repeat("long", 100),
+		}
+
+		for i, key := range keys {
+			require.NoError(t, c.Put(key, i, nil), key)
+		}
+		for i, key := range keys {
+			assertInt(t, c, key, int64(i))
+		}
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		c := newCache(t)
+		large := strings.Repeat("x", 64<<10)
+
+		require.NoError(t, c.Put("large", large, nil))
+		assertValue(t, c, "large", large)
+
+		require.NoError(t, c.Put("unicode", "سلام دنیا", nil))
+		assertValue(t, c, "unicode", "سلام دنیا")
+
+		require.NoError(t, c.Put("spaces", "  padded\r\nlines  ", nil))
+		assertValue(t, c, "spaces", "  padded\r\nlines  ")
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		c := newCache(t)
+		const workers = 20
+		const increments = 10
+
+		require.NoError(t, c.Put("counter", 0, nil))
+
+		var wg sync.WaitGroup
+		for worker := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				key := "worker" + strconv.Itoa(worker)
+				assert.NoError(t, c.Put(key, worker, nil))
+				for range increments {
+					_, err := c.Increment("counter", 1)
+					assert.NoError(t, err)
+				}
+
+				caster, err := c.Cast(key)
+				if assert.NoError(t, err) {
+					assert.Equal(t, worker, caster.IntSafe(-1))
+				}
+			}()
+		}
+		wg.Wait()
+
+		assertInt(t, c, "counter", workers*increments)
+	})
+}
+
+// assertValue asserts that key holds val.
+func assertValue(t *testing.T, c cache.Cache, key, val string) {
+	t.Helper()
+
+	got, err := c.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, val, cast.NewCaster(got).StringSafe(""), "value of %q", key)
+}
+
+// assertInt asserts that key holds the integer val.
+func assertInt(t *testing.T, c cache.Cache, key string, val int64) {
+	t.Helper()
+
+	caster, err := c.Cast(key)
+	require.NoError(t, err)
+	assert.Equal(t, val, caster.Int64Safe(-1), "value of %q", key)
+}
+
+// assertMissing asserts that key does not exist.
+func assertMissing(t *testing.T, c cache.Cache, key string) {
+	t.Helper()
+
+	exists, err := c.Exists(key)
+	require.NoError(t, err)
+	assert.False(t, exists, "%q exists", key)
+}
+
+// assertTTL asserts that key expires within ttl, allowing for TTL granularity
+// of seconds and the time spent by the test.
+func assertTTL(t *testing.T, c cache.Cache, key string, ttl time.Duration) {
+	t.Helper()
+
+	got, err := c.TTL(key)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, got, ttl, "TTL of %q", key)
+	assert.Greater(t, got, ttl-5*time.Second, "TTL of %q", key)
+}
@@ -0,0 +1,85 @@
+// Package cachetest provides conformance test suites for implementations of the
+// cache package interfaces, so that third-party backends can be validated against
+// the same behaviour as the built-in ones.
+//
+//	func TestMyCache(t *testing.T) {
+//		cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+//			return NewMyCache(t.TempDir())
+//		})
+//	}
+package cachetest
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+)
+
+// CacheFactory creates an empty cache for a single test.
+// Caches created for different tests must not share keys.
+type CacheFactory func(t *testing.T) cache.Cache
+
+// QueueFactory creates an empty queue for a single test.
+type QueueFactory func(t *testing.T) cache.Queue
+
+// Option configures a suite run.
+type Option func(*config)
+
+// config holds the settings of a suite run.
+type config struct {
+	advance func(d time.Duration)
+	expiry  bool
+}
+
+// newConfig creates the default settings and applies the given options.
+func newConfig(opts ...Option) config {
+	c := config{
+		advance: time.Sleep,
+		expiry:  true,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+
+	return c
+}
+
+// WithAdvance sets how the suites let time pass to test expiry, such as
+// advancing a cache.FakeClock shared with the backend. Defaults to time.Sleep.
+func WithAdvance(advance func(d time.Duration)) Option {
+	return func(c *config) {
+		if advance != nil {
+			c.advance = advance
+		}
+	}
+}
+
+// WithoutExpiry skips the tests relying on entries expiring,
+// for backends whose time cannot be advanced, such as test servers.
+func WithoutExpiry() Option {
+	return func(c *config) {
+		c.expiry = false
+	}
+}
+
+// skipExpiry skips the current test if expiry is disabled.
+func (c config) skipExpiry(t *testing.T) {
+	t.Helper()
+	if !c.expiry {
+		t.Skip("expiry disabled with WithoutExpiry")
+	}
+}
+
+// sequence makes the names of suite objects unique within the process.
+var sequence atomic.Int64
+
+// uniqueName returns a name that is not used by other tests of the process.
+func uniqueName(prefix string) string {
+	return prefix + "-" + strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatInt(sequence.Add(1), 10)
+}
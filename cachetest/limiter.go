@@ -0,0 +1,119 @@
+package cachetest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunLimiterSuite runs the conformance tests of cache.RateLimiter over the caches
+// created by newCache, validating the cache semantics the limiter relies on.
+func RunLimiterSuite(t *testing.T, newCache CacheFactory, opts ...Option) {
+	cfg := newConfig(opts...)
+
+	t.Run("Hits", func(t *testing.T) {
+		ttl := time.Minute
+		limiter := cache.NewRateLimiter(uniqueName("limiter"), 3, ttl, newCache(t))
+
+		locked, err := limiter.MustLock()
+		require.NoError(t, err)
+		assert.False(t, locked)
+
+		total, err := limiter.TotalAttempts()
+		require.NoError(t, err)
+		assert.Zero(t, total)
+
+		for i := range 3 {
+			require.NoError(t, limiter.Hit())
+
+			retries, err := limiter.RetriesLeft()
+			require.NoError(t, err)
+			assert.EqualValues(t, 2-i, retries)
+
+			total, err := limiter.TotalAttempts()
+			require.NoError(t, err)
+			assert.EqualValues(t, i+1, total)
+		}
+
+		locked, err = limiter.MustLock()
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		available, err := limiter.AvailableIn()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, available, ttl)
+		assert.Greater(t, available, ttl-5*time.Second)
+
+		// Hits past the limit keep the limiter locked
+		require.NoError(t, limiter.Hit())
+		retries, err := limiter.RetriesLeft()
+		require.NoError(t, err)
+		assert.Zero(t, retries)
+	})
+
+	t.Run("Lock, Reset and Clear", func(t *testing.T) {
+		limiter := cache.NewRateLimiter(uniqueName("limiter"), 5, time.Minute, newCache(t))
+
+		require.NoError(t, limiter.Lock())
+		locked, err := limiter.MustLock()
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		require.NoError(t, limiter.Reset())
+		retries, err := limiter.RetriesLeft()
+		require.NoError(t, err)
+		assert.EqualValues(t, 5, retries)
+		locked, err = limiter.MustLock()
+		require.NoError(t, err)
+		assert.False(t, locked)
+
+		require.NoError(t, limiter.Clear())
+		retries, err = limiter.RetriesLeft()
+		require.NoError(t, err)
+		assert.Zero(t, retries)
+		locked, err = limiter.MustLock()
+		require.NoError(t, err)
+		assert.False(t, locked)
+	})
+
+	t.Run("Window expiry", func(t *testing.T) {
+		cfg.skipExpiry(t)
+		limiter := cache.NewRateLimiter(uniqueName("limiter"), 1, time.Second, newCache(t))
+
+		require.NoError(t, limiter.Hit())
+		locked, err := limiter.MustLock()
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		cfg.advance(1500 * time.Millisecond)
+		locked, err = limiter.MustLock()
+		require.NoError(t, err)
+		assert.False(t, locked)
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		const workers = 20
+		limiter := cache.NewRateLimiter(uniqueName("limiter"), 100, time.Minute, newCache(t))
+
+		// The first hit creates the window, later hits decrement it
+		require.NoError(t, limiter.Hit())
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, limiter.Hit())
+			}()
+		}
+		wg.Wait()
+
+		total, err := limiter.TotalAttempts()
+		require.NoError(t, err)
+		assert.EqualValues(t, workers+1, total)
+	})
+}
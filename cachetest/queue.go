@@ -0,0 +1,120 @@
+package cachetest
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-universal/cast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunQueueSuite runs the conformance tests of the cache.Queue interface against
+// the queues created by newQueue. Items are pulled from the front of the queue
+// in the order they were pushed and popped from the back.
+func RunQueueSuite(t *testing.T, newQueue QueueFactory) {
+	t.Run("Push and Length", func(t *testing.T) {
+		q := newQueue(t)
+
+		length, err := q.Length()
+		require.NoError(t, err)
+		assert.Zero(t, length)
+
+		for i := range 3 {
+			require.NoError(t, q.Push(i))
+		}
+
+		length, err = q.Length()
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, length)
+	})
+
+	t.Run("Pull and Pop", func(t *testing.T) {
+		q := newQueue(t)
+		for _, item := range []string{"first", "middle", "last"} {
+			require.NoError(t, q.Push(item))
+		}
+
+		item, err := q.Pull()
+		require.NoError(t, err)
+		assert.Equal(t, "first", cast.NewCaster(item).StringSafe(""))
+
+		item, err = q.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, "last", cast.NewCaster(item).StringSafe(""))
+
+		caster, err := q.Cast()
+		require.NoError(t, err)
+		assert.Equal(t, "middle", caster.StringSafe(""))
+
+		length, err := q.Length()
+		require.NoError(t, err)
+		assert.Zero(t, length)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		q := newQueue(t)
+
+		item, err := q.Pull()
+		require.NoError(t, err)
+		assert.Nil(t, item)
+
+		item, err = q.Pop()
+		require.NoError(t, err)
+		assert.Nil(t, item)
+
+		caster, err := q.Cast()
+		require.NoError(t, err)
+		assert.True(t, caster.IsNil())
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		q := newQueue(t)
+		require.NoError(t, q.Push("item"))
+
+		require.NoError(t, q.Clear())
+		length, err := q.Length()
+		require.NoError(t, err)
+		assert.Zero(t, length)
+
+		require.NoError(t, q.Clear())
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		q := newQueue(t)
+		const workers = 20
+
+		var wg sync.WaitGroup
+		for worker := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, q.Push(strconv.Itoa(worker)))
+			}()
+		}
+		wg.Wait()
+
+		seen := make(map[string]bool)
+		var mutex sync.Mutex
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				caster, err := q.Cast()
+				assert.NoError(t, err)
+
+				mutex.Lock()
+				seen[caster.StringSafe("")] = true
+				mutex.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, seen, workers)
+		length, err := q.Length()
+		require.NoError(t, err)
+		assert.Zero(t, length)
+	})
+}
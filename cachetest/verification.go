@@ -0,0 +1,94 @@
+package cachetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunVerificationSuite runs the conformance tests of cache.VerificationCode over
+// the caches created by newCache, validating the cache semantics it relies on.
+func RunVerificationSuite(t *testing.T, newCache CacheFactory, opts ...Option) {
+	cfg := newConfig(opts...)
+
+	t.Run("Set and Validate", func(t *testing.T) {
+		ttl := time.Minute
+		code := cache.NewVerification(uniqueName("verify"), ttl, newCache(t))
+
+		exists, err := code.Exists()
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		val, err := code.Get()
+		require.NoError(t, err)
+		assert.Empty(t, val)
+
+		ok, err := code.Validate("")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, code.Set("123456"))
+		val, err = code.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "123456", val)
+
+		ok, err = code.Validate("123456")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = code.Validate("654321")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		remaining, err := code.TTL()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, remaining, ttl)
+		assert.Greater(t, remaining, ttl-5*time.Second)
+	})
+
+	t.Run("Generate", func(t *testing.T) {
+		code := cache.NewVerification(uniqueName("verify"), time.Minute, newCache(t))
+
+		val, err := code.Generate(6)
+		require.NoError(t, err)
+		assert.Regexp(t, `^[0-9]{6}$`, val)
+
+		ok, err := code.Validate(val)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		code := cache.NewVerification(uniqueName("verify"), time.Minute, newCache(t))
+
+		require.NoError(t, code.Set("123456"))
+		require.NoError(t, code.Clear())
+
+		ok, err := code.Validate("123456")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		exists, err := code.Exists()
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		cfg.skipExpiry(t)
+		code := cache.NewVerification(uniqueName("verify"), time.Second, newCache(t))
+
+		require.NoError(t, code.Set("123456"))
+
+		// Setting a new code keeps the original TTL
+		cfg.advance(500 * time.Millisecond)
+		require.NoError(t, code.Set("654321"))
+
+		cfg.advance(time.Second)
+		ok, err := code.Validate("654321")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
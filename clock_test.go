@@ -0,0 +1,130 @@
+package cache_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent logging.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := cache.NewFakeClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	timer := clock.After(time.Minute)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-timer:
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case now := <-timer:
+		assert.Equal(t, start.Add(time.Minute), now)
+	default:
+		t.Fatal("timer not fired")
+	}
+
+	t.Run("Memory cache expiry", func(t *testing.T) {
+		clock := cache.NewFakeClock(start)
+		c := cache.NewMemoryCache(cache.WithClock(clock))
+
+		ttl := time.Hour
+		require.NoError(t, c.Put("key", "value", &ttl))
+
+		clock.Advance(59 * time.Minute)
+		remaining, err := c.TTL("key")
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, remaining)
+
+		clock.Advance(2 * time.Minute)
+		exists, err := c.Exists("key")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Janitor", func(t *testing.T) {
+		clock := cache.NewFakeClock(start)
+		logs := &syncBuffer{}
+		c := cache.NewMemoryCache(
+			cache.WithClock(clock),
+			cache.WithCleanupInterval(time.Minute),
+			cache.WithLogger(slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		)
+		defer c.(io.Closer).Close()
+
+		ttl := time.Second
+		require.NoError(t, c.Put("session", "value", &ttl))
+
+		assert.Eventually(t, func() bool {
+			clock.Advance(time.Minute)
+			return strings.Contains(logs.String(), "key=session reason=expired")
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("Rate limiter", func(t *testing.T) {
+		clock := cache.NewFakeClock(start)
+		limiter := cache.NewRateLimiter("login", 2, time.Minute, cache.NewMemoryCache(cache.WithClock(clock)))
+
+		require.NoError(t, limiter.Hit())
+		require.NoError(t, limiter.Hit())
+		locked, err := limiter.MustLock()
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		available, err := limiter.AvailableIn()
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, available)
+
+		clock.Advance(time.Minute + time.Second)
+		locked, err = limiter.MustLock()
+		require.NoError(t, err)
+		assert.False(t, locked)
+	})
+
+	t.Run("Verification", func(t *testing.T) {
+		clock := cache.NewFakeClock(start)
+		verification := cache.NewVerification("signup", 2*time.Minute, cache.NewMemoryCache(cache.WithClock(clock)))
+
+		code, err := verification.Generate(6)
+		require.NoError(t, err)
+
+		clock.Advance(time.Minute)
+		ok, err := verification.Validate(code)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		clock.Advance(2 * time.Minute)
+		ok, err = verification.Validate(code)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Codec encodes values to bytes and decodes them back.
+type Codec interface {
+	// Name identifies the codec in encoded data.
+	Name() string
+
+	// Marshal encodes value.
+	Marshal(value any) ([]byte, error)
+
+	// Unmarshal decodes data into the value pointed to by target.
+	Unmarshal(data []byte, target any) error
+}
+
+var (
+	// JSONCodec encodes values with encoding/json.
+	// Values decoded into an interface use the generic JSON types.
+	JSONCodec Codec = jsonCodec{}
+
+	// GobCodec encodes values with encoding/gob, preserving their Go types.
+	// Values are sent as interfaces, so custom types must be registered with
+	// gob.Register before they are encoded or decoded.
+	GobCodec Codec = gobCodec{}
+)
+
+// init registers the generic JSON-like types, which gob does not register itself.
+func init() {
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// jsonCodec is the JSON implementation of the Codec interface.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	return data, nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, target any) error {
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	return nil
+}
+
+// gobCodec is the gob implementation of the Codec interface.
+// Values are wrapped in an envelope so that they can be decoded into interfaces.
+type gobCodec struct{}
+
+// gobEnvelope wraps a value encoded by the gob codec.
+type gobEnvelope struct {
+	Value any
+}
+
+func (gobCodec) Name() string {
+	return "gob"
+}
+
+func (gobCodec) Marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobEnvelope{Value: value}); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, target any) error {
+	var envelope gobEnvelope
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&envelope); err != nil {
+		return fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("%w: target must be a non-nil pointer", ErrCodec)
+	}
+
+	dst := ptr.Elem()
+	if envelope.Value == nil {
+		dst.SetZero()
+		return nil
+	}
+
+	src := reflect.ValueOf(envelope.Value)
+	if !src.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("%w: cannot decode %s into %s", ErrCodec, src.Type(), dst.Type())
+	}
+
+	dst.Set(src)
+	return nil
+}
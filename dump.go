@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	// dumpFormat identifies the dump format in its header.
+	dumpFormat = "go-universal/cache"
+
+	// dumpVersion is the version of the dump format.
+	dumpVersion = 1
+)
+
+// Enumerable is implemented by caches that can list their keys.
+type Enumerable interface {
+	// Range calls fn for each live key until fn returns false.
+	Range(fn func(key string) bool) error
+}
+
+// dumpHeader is the first line of a dump.
+type dumpHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	Codec     string    `json:"codec"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// dumpRecord is a single entry of a dump.
+// The expiry is absolute unix milliseconds, omitted for entries without expiry.
+type dumpRecord struct {
+	Key       string `json:"key"`
+	Value     []byte `json:"value"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+// Export writes all entries of cache to w and returns the number of entries written.
+// The dump is a stream of JSON lines, a header followed by one line per entry,
+// with values encoded by the codec set with WithCodec (JSONCodec by default).
+// The cache must implement Enumerable. Keys the cache cannot enumerate, such as
+// Redis keys hashed for length, are reported with ErrUndecodableKey once the
+// other entries are written.
+func Export(cache Cache, w io.Writer, opts ...Option) (int, error) {
+	enumerable, ok := cache.(Enumerable)
+	if !ok {
+		return 0, errors.New("cache is not enumerable")
+	}
+
+	o := newOption(opts...)
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(dumpHeader{
+		Format:    dumpFormat,
+		Version:   dumpVersion,
+		Codec:     o.codec.Name(),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var rangeErr error
+	err = enumerable.Range(func(key string) bool {
+		var record *dumpRecord
+		record, rangeErr = exportRecord(cache, key, o.codec)
+		if rangeErr != nil || record == nil {
+			return rangeErr == nil
+		}
+
+		if rangeErr = encoder.Encode(record); rangeErr != nil {
+			return false
+		}
+
+		count++
+		return true
+	})
+
+	return count, errors.Join(err, rangeErr)
+}
+
+// Import reads a dump written by Export from r and stores its entries in cache,
+// skipping entries expired since. It returns the number of entries stored.
+// The codec must match the one used to export.
+func Import(cache Cache, r io.Reader, opts ...Option) (int, error) {
+	o := newOption(opts...)
+	decoder := json.NewDecoder(r)
+
+	var header dumpHeader
+	if err := decoder.Decode(&header); err != nil {
+		return 0, fmt.Errorf("invalid dump header: %w", err)
+	}
+
+	switch {
+	case header.Format != dumpFormat:
+		return 0, fmt.Errorf("unknown dump format %q", header.Format)
+	case header.Version != dumpVersion:
+		return 0, fmt.Errorf("unsupported dump version %d", header.Version)
+	case header.Codec != o.codec.Name():
+		return 0, fmt.Errorf("dump codec %q does not match %q", header.Codec, o.codec.Name())
+	}
+
+	count := 0
+	for {
+		var record dumpRecord
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("invalid dump record: %w", err)
+		}
+
+		var ttl *time.Duration
+		if record.ExpiresAt > 0 {
+			remaining := time.Until(time.UnixMilli(record.ExpiresAt))
+			if remaining <= 0 {
+				continue
+			}
+			ttl = &remaining
+		}
+
+		var value any
+		if err := o.codec.Unmarshal(record.Value, &value); err != nil {
+			return count, &OpError{Op: "import", Key: record.Key, Err: err}
+		}
+
+		if err := cache.Put(record.Key, value, ttl); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+}
+
+// exportRecord reads the entry of key, returning nil if it no longer exists.
+func exportRecord(cache Cache, key string, codec Codec) (*dumpRecord, error) {
+	value, exists, err := Lookup(cache, key)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	ttl, exists, err := remainingTTL(cache, key)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return nil, &OpError{Op: "export", Key: key, Err: err}
+	}
+
+	record := &dumpRecord{Key: key, Value: data}
+	if ttl != nil {
+		record.ExpiresAt = time.Now().Add(*ttl).UnixMilli()
+	}
+
+	return record, nil
+}
+
+// remainingTTL returns the remaining TTL of key, nil for entries without expiry.
+// Returns false if the key no longer exists, reported with a zero TTL
+// or, by the Redis cache, with -2.
+func remainingTTL(cache Cache, key string) (*time.Duration, bool, error) {
+	ttl, err := cache.TTL(key)
+	switch {
+	case err != nil:
+		return nil, false, err
+	case ttl == -1 || ttl == time.Duration(math.MaxInt64):
+		return nil, true, nil
+	case ttl <= 0:
+		return nil, false, nil
+	default:
+		return &ttl, true, nil
+	}
+}
@@ -0,0 +1,122 @@
+package cache_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	t.Run("Memory to Redis", func(t *testing.T) {
+		source := cache.NewMemoryCache()
+		ttl := time.Hour
+		require.NoError(t, source.Put("first", "one", nil))
+		require.NoError(t, source.Put("second", "two", &ttl))
+
+		// Data structures are not exported
+		require.NoError(t, source.(cache.HashCache).HSet("hash", map[string]any{"field": 1}, nil))
+		require.NoError(t, source.(cache.SetCache).SAdd("set", []string{"member"}, nil))
+
+		var buf bytes.Buffer
+		n, err := cache.Export(source, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		target := cache.NewRedisCache("dump", redis.NewClient(&redis.Options{}))
+		n, err = cache.Import(target, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		val, err := target.Get("first")
+		require.NoError(t, err)
+		assert.Equal(t, "one", val)
+
+		remaining, err := target.TTL("second")
+		require.NoError(t, err)
+		assert.Greater(t, remaining, 59*time.Minute)
+
+		buf.Reset()
+		n, err = cache.Export(target, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("Gob codec", func(t *testing.T) {
+		source, err := cache.NewDiskCache(filepath.Join(t.TempDir(), "cache"))
+		require.NoError(t, err)
+		require.NoError(t, source.Put("number", 42, nil))
+		require.NoError(t, source.Put("list", []string{"a", "b"}, nil))
+
+		var buf bytes.Buffer
+		_, err = cache.Export(source, &buf, cache.WithCodec(cache.GobCodec))
+		require.NoError(t, err)
+
+		target := cache.NewMemoryCache()
+		_, err = cache.Import(target, bytes.NewReader(buf.Bytes()), cache.WithCodec(cache.GobCodec))
+		require.NoError(t, err)
+
+		val, err := target.Get("number")
+		require.NoError(t, err)
+		assert.Equal(t, 42, val)
+
+		val, err = target.Get("list")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, val)
+
+		_, err = cache.Import(target, bytes.NewReader(buf.Bytes()))
+		assert.Error(t, err)
+
+		// Custom types must be registered by the caller
+		unregistered := cache.NewMemoryCache()
+		require.NoError(t, unregistered.Put("point", struct{ X, Y int }{1, 2}, nil))
+		_, err = cache.Export(unregistered, &bytes.Buffer{}, cache.WithCodec(cache.GobCodec))
+		assert.ErrorIs(t, err, cache.ErrCodec)
+	})
+
+	t.Run("Hashed keys", func(t *testing.T) {
+		source := cache.NewRedisCache(uniquePrefix("dump"), redis.NewClient(&redis.Options{}))
+		require.NoError(t, source.Put("short", "value", nil))
+		require.NoError(t, source.Put(strings.Repeat("long", 100), "value", nil))
+
+		// Keys hashed for length are reported after the others are exported
+		var buf bytes.Buffer
+		n, err := cache.Export(source, &buf)
+		assert.ErrorIs(t, err, cache.ErrUndecodableKey)
+		assert.Equal(t, 1, n)
+		assert.Contains(t, buf.String(), `"key":"short"`)
+	})
+
+	t.Run("Expired entries", func(t *testing.T) {
+		dump := `{"format":"go-universal/cache","version":1,"codec":"json","created_at":"2024-01-01T00:00:00Z"}
+{"key":"expired","value":"InZhbHVlIg==","expires_at":1}
+{"key":"live","value":"InZhbHVlIg=="}
+`
+		target := cache.NewMemoryCache()
+		n, err := cache.Import(target, strings.NewReader(dump))
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		val, err := target.Get("live")
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+	})
+
+	t.Run("Invalid dumps", func(t *testing.T) {
+		target := cache.NewMemoryCache()
+		_, err := cache.Import(target, strings.NewReader(`{"format":"other","version":1}`))
+		assert.Error(t, err)
+
+		_, err = cache.Import(target, strings.NewReader(`{"format":"go-universal/cache","version":99,"codec":"json"}`))
+		assert.Error(t, err)
+
+		_, err = cache.Export(cache.NewMemcachedCache("test", "127.0.0.1:1"), &bytes.Buffer{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotFound is reported by loaders and Remember when no value exists for a key.
+	// Cache methods report missing keys with a nil value and Lookup instead.
+	ErrNotFound = errors.New("key not found")
+
+	// ErrNotNumeric is reported when a numeric operation targets a non-numeric value.
+	ErrNotNumeric = errors.New("value is not numeric")
+
+	// ErrWrongType is reported when an operation targets a value of another data type,
+	// such as a hash operation on a scalar value.
+	ErrWrongType = errors.New("value has the wrong type")
+
+	// ErrUndecodableKey is reported by Range when backend keys cannot be mapped back
+	// to cache keys, such as keys hashed for length. The other keys are still enumerated.
+	ErrUndecodableKey = errors.New("key cannot be decoded")
+
+	// ErrCodec is reported when a value cannot be encoded or decoded.
+	ErrCodec = errors.New("value cannot be encoded or decoded")
+
+	// ErrBackendUnavailable is reported when the cache backend cannot be reached.
+	ErrBackendUnavailable = errors.New("backend unavailable")
+)
+
+// OpError records a failed cache operation and the key it was applied to.
+type OpError struct {
+	Op  string
+	Key string
+	Err error
+}
+
+func (e *OpError) Error() string {
+	return "cache: " + e.Op + " " + strconv.Quote(e.Key) + ": " + e.Err.Error()
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// redisError maps a go-redis error to the package errors.
+// redis.Nil is treated as no error.
+func redisError(op, key string, err error) error {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	var reply redis.Error
+	switch {
+	case errors.Is(err, redis.ErrClosed):
+		err = fmt.Errorf("%w: %w", ErrBackendUnavailable, err)
+	case errors.As(err, &reply):
+		msg := reply.Error()
+		for _, known := range redisReplies {
+			// Scripts report the replies of failing redis.call after their own prefix
+			if strings.HasPrefix(msg, known.prefix) || strings.Contains(msg, ": "+known.prefix) {
+				err = fmt.Errorf("%w: %w", known.err, err)
+				break
+			}
+		}
+	case strings.HasPrefix(err.Error(), "redis: can't marshal"):
+		err = fmt.Errorf("%w: %w", ErrCodec, err)
+	}
+
+	return backendError(op, key, err)
+}
+
+// redisReplies maps the error replies of the Redis server to the package errors.
+var redisReplies = []struct {
+	prefix string
+	err    error
+}{
+	{"WRONGTYPE ", ErrWrongType},
+	{"ERR value is not an integer", ErrNotNumeric},
+	{"ERR value is not a valid float", ErrNotNumeric},
+	{"ERR hash value is not an integer", ErrNotNumeric},
+	{"ERR hash value is not a float", ErrNotNumeric},
+	{"ERR increment or decrement would overflow", ErrNotNumeric},
+}
+
+// backendError wraps err into an OpError and marks network failures
+// as ErrBackendUnavailable. Errors already wrapped are returned as is.
+func backendError(op, key string, err error) error {
+	var opErr *OpError
+	if err == nil || errors.As(err, &opErr) {
+		return err
+	}
+
+	var netErr net.Error
+	unavailable := errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, context.DeadlineExceeded)
+	if unavailable && !errors.Is(err, ErrBackendUnavailable) {
+		err = fmt.Errorf("%w: %w", ErrBackendUnavailable, err)
+	}
+
+	return &OpError{Op: op, Key: key, Err: err}
+}
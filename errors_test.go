@@ -0,0 +1,88 @@
+package cache_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("Not numeric", func(t *testing.T) {
+		caches := map[string]cache.Cache{
+			"memory": cache.NewMemoryCache(),
+			"redis":  cache.NewRedisCache("test", redis.NewClient(&redis.Options{})),
+		}
+
+		for name, c := range caches {
+			t.Run(name, func(t *testing.T) {
+				err := c.Put("errorKey", "text", nil)
+				require.NoError(t, err)
+
+				_, err = c.Increment("errorKey", 1)
+				assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+				var opErr *cache.OpError
+				require.ErrorAs(t, err, &opErr)
+				assert.Equal(t, "increment", opErr.Op)
+				assert.Equal(t, "errorKey", opErr.Key)
+			})
+		}
+	})
+
+	t.Run("Redis replies", func(t *testing.T) {
+		c := cache.NewRedisCache(uniquePrefix("errors"), redis.NewClient(&redis.Options{}))
+		require.NoError(t, c.Put("text", "text", nil))
+		require.NoError(t, c.(cache.HashCache).HSet("hash", map[string]any{"field": "text"}, nil))
+
+		_, err := c.Increment("text", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+		_, err = c.IncrementFloat("text", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+		_, err = c.(cache.HashCache).HIncrBy("hash", "field", 1)
+		assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+		_, err = c.Get("hash")
+		assert.ErrorIs(t, err, cache.ErrWrongType)
+		_, err = c.(cache.SortedSetCache).ZIncrBy("text", "member", 1)
+		assert.ErrorIs(t, err, cache.ErrWrongType)
+	})
+
+	t.Run("Disk failures", func(t *testing.T) {
+		dir := t.TempDir()
+		c, err := cache.NewDiskCache(dir, cache.WithCleanupInterval(0))
+		require.NoError(t, err)
+		require.NoError(t, c.Put("key", "value", nil))
+
+		// Corrupt the file of the key
+		err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() {
+				err = os.WriteFile(path, []byte("corrupt"), 0o644)
+			}
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = c.Get("key")
+		assert.ErrorIs(t, err, cache.ErrCodec)
+
+		var opErr *cache.OpError
+		require.ErrorAs(t, err, &opErr)
+		assert.Equal(t, "lookup", opErr.Op)
+	})
+
+	t.Run("Backend unavailable", func(t *testing.T) {
+		c := cache.NewRedisCache("test", redis.NewClient(&redis.Options{
+			Addr:       "127.0.0.1:1",
+			MaxRetries: -1,
+		}))
+
+		_, err := c.Get("key")
+		assert.ErrorIs(t, err, cache.ErrBackendUnavailable)
+	})
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errCallPanicked is reported to the callers of a shared call that panicked.
+var errCallPanicked = errors.New("call panicked")
+
+// flight is an in-progress call shared by concurrent callers.
+type flight struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// flightGroup deduplicates concurrent calls with the same key.
+type flightGroup struct {
+	mutex   sync.Mutex
+	flights map[string]*flight
+}
+
+// do calls fn once for concurrent calls with the same key,
+// sharing its result with every caller. fn runs detached from the cancellation
+// of ctx, so a caller giving up does not fail the others, and each caller stops
+// waiting once its own ctx is done. A panic in fn is returned as an error.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	g.mutex.Lock()
+	f, ok := g.flights[key]
+	if !ok {
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+
+		f = &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		go g.run(context.WithoutCancel(ctx), key, f, fn)
+	}
+	g.mutex.Unlock()
+
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run calls fn for the flight f and releases its callers.
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) (any, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.val, f.err = nil, &OpError{Op: "call", Key: key, Err: fmt.Errorf("%w: %v", errCallPanicked, r)}
+		}
+
+		g.mutex.Lock()
+		delete(g.flights, key)
+		g.mutex.Unlock()
+		close(f.done)
+	}()
+
+	f.val, f.err = fn(ctx)
+}
+
+// busy reports whether a call with the given key is in progress.
+func (g *flightGroup) busy(key string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	_, ok := g.flights[key]
+	return ok
+}
@@ -0,0 +1,32 @@
+package cache
+
+import "time"
+
+// HashCache is implemented by caches that store hashes, maps of fields to values
+// that can be read and updated field by field. The caches returned by
+// NewMemoryCache and NewRedisCache implement it.
+//
+// The TTL applies to the whole hash. Operations on keys holding a scalar value
+// fail with ErrWrongType, as do Get and Lookup on hashes, and hashes whose last
+// field is deleted are removed.
+type HashCache interface {
+	// HGet retrieves the value of a hash field.
+	// Returns nil if the key or field does not exist.
+	HGet(key, field string) (any, error)
+
+	// HSet stores the fields of values in the hash at key. If the key does not exist,
+	// the hash is created with the specified TTL, or indefinitely if ttl is nil.
+	// Existing hashes keep their TTL.
+	HSet(key string, values map[string]any, ttl *time.Duration) error
+
+	// HDel removes fields from the hash at key.
+	HDel(key string, fields ...string) error
+
+	// HGetAll retrieves all fields of the hash at key.
+	// Returns an empty map if the key does not exist.
+	HGetAll(key string) (map[string]any, error)
+
+	// HIncrBy increments the integer value of a hash field and returns the new value.
+	// Missing fields start at zero and missing hashes are created without TTL.
+	HIncrBy(key, field string, value int64) (int64, error)
+}
@@ -0,0 +1,149 @@
+package cache_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cast"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashCache(t *testing.T) {
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache("hash "+time.Now().Format(time.RFC3339Nano), redis.NewClient(&redis.Options{})),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			hashes, ok := c.(cache.HashCache)
+			require.True(t, ok)
+
+			ttl := time.Minute
+			err := hashes.HSet("user", map[string]any{"name": "John", "age": 30}, &ttl)
+			require.NoError(t, err)
+
+			val, err := hashes.HGet("user", "name")
+			require.NoError(t, err)
+			assert.Equal(t, "John", cast.NewCaster(val).StringSafe(""))
+
+			val, err = hashes.HGet("user", "missing")
+			require.NoError(t, err)
+			assert.Nil(t, val)
+
+			_, err = c.Get("user")
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+
+			// Existing hashes keep their TTL
+			err = hashes.HSet("user", map[string]any{"name": "Jane"}, nil)
+			require.NoError(t, err)
+			remaining, err := c.TTL("user")
+			require.NoError(t, err)
+			assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+
+			age, err := hashes.HIncrBy("user", "age", 2)
+			require.NoError(t, err)
+			assert.EqualValues(t, 32, age)
+
+			visits, err := hashes.HIncrBy("user", "visits", 1)
+			require.NoError(t, err)
+			assert.EqualValues(t, 1, visits)
+
+			_, err = hashes.HIncrBy("user", "name", 1)
+			assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+			all, err := hashes.HGetAll("user")
+			require.NoError(t, err)
+			assert.Len(t, all, 3)
+			assert.Equal(t, "Jane", cast.NewCaster(all["name"]).StringSafe(""))
+			assert.EqualValues(t, 32, cast.NewCaster(all["age"]).IntSafe(0))
+
+			err = hashes.HDel("user", "name", "age")
+			require.NoError(t, err)
+			all, err = hashes.HGetAll("user")
+			require.NoError(t, err)
+			assert.Len(t, all, 1)
+
+			err = hashes.HDel("user", "visits")
+			require.NoError(t, err)
+			exists, err := c.Exists("user")
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			all, err = hashes.HGetAll("user")
+			require.NoError(t, err)
+			assert.Empty(t, all)
+
+			err = c.Put("scalar", "value", nil)
+			require.NoError(t, err)
+			_, err = hashes.HGet("scalar", "field")
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+			err = hashes.HSet("scalar", map[string]any{"field": 1}, nil)
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+		})
+	}
+
+	t.Run("Expiry", func(t *testing.T) {
+		hashes := cache.NewMemoryCache().(cache.HashCache)
+
+		ttl := 10 * time.Millisecond
+		err := hashes.HSet("session", map[string]any{"user": "John"}, &ttl)
+		require.NoError(t, err)
+
+		time.Sleep(20 * time.Millisecond)
+		all, err := hashes.HGetAll("session")
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("Snapshot", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		err := c.(cache.HashCache).HSet("user", map[string]any{"name": "John"}, nil)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.(cache.Snapshotter).Snapshot(&buf))
+
+		restored := cache.NewMemoryCache()
+		require.NoError(t, restored.(cache.Snapshotter).Restore(&buf))
+		val, err := restored.(cache.HashCache).HGet("user", "name")
+		require.NoError(t, err)
+		assert.Equal(t, "John", val)
+	})
+
+	t.Run("Snapshot while writing", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		hashes := c.(cache.HashCache)
+		require.NoError(t, hashes.HSet("user", map[string]any{"visits": 0}, nil))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 100 {
+				assert.NoError(t, hashes.HSet("user", map[string]any{strconv.Itoa(i): i}, nil))
+				_, err := hashes.HIncrBy("user", "visits", 1)
+				assert.NoError(t, err)
+				assert.NoError(t, hashes.HDel("user", strconv.Itoa(i)))
+			}
+		}()
+
+		for writing := true; writing; {
+			select {
+			case <-done:
+				writing = false
+			default:
+				require.NoError(t, c.(cache.Snapshotter).Snapshot(io.Discard))
+			}
+		}
+
+		visits, err := hashes.HGet("user", "visits")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), visits)
+	})
+}
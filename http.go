@@ -0,0 +1,397 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPCacheConfig configures the HTTP response caching middleware.
+type HTTPCacheConfig struct {
+	// TTL is the lifetime of responses without a max-age or s-maxage directive.
+	// Zero only caches responses with one of these directives.
+	TTL time.Duration
+
+	// Query lists the query parameters included in cache keys.
+	// Nil includes every parameter.
+	Query []string
+
+	// MaxBodySize is the largest response body cached, larger responses are
+	// streamed to the client and not cached. Zero uses 10 MiB.
+	MaxBodySize int
+
+	// StoreCookies caches responses setting cookies. Cached cookies are sent to
+	// every client, so it should only be enabled for caches serving a single user.
+	StoreCookies bool
+}
+
+// httpMaxBody is the default limit of response bodies held in memory to be cached.
+const httpMaxBody = 10 << 20
+
+// httpEntry is a cached HTTP response.
+type httpEntry struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	Stored time.Time   `json:"stored"`
+}
+
+// NewHTTPMiddleware creates a net/http middleware caching GET responses in cache.
+//
+// Responses are keyed by scheme, host, path, the configured query parameters and the
+// request headers named by their Vary header. HEAD requests are answered from cached
+// GET responses and passed to the handler on a miss without being cached. Request and response Cache-Control
+// directives are honored: no-store and private responses are never cached, max-age and
+// s-maxage set the lifetime, and requests with no-cache or no-store bypass the cache.
+// Responses to requests with an Authorization header are only cached when marked public,
+// and responses setting cookies only with StoreCookies. Cacheability is decided from
+// the response headers: other responses, responses over MaxBodySize and responses
+// flushed by the handler are streamed to the client without being buffered.
+// Cached responses get a strong ETag derived from their body unless they already
+// have one, and matching If-None-Match requests are answered with 304 Not Modified.
+// Entries are encoded with the codec set with WithCodec.
+func NewHTTPMiddleware(cache Cache, config HTTPCacheConfig, opts ...Option) func(http.Handler) http.Handler {
+	o := newOption(opts...)
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = httpMaxBody
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			directives := parseCacheControl(r.Header.Get("Cache-Control"))
+			_, noStore := directives["no-store"]
+			_, noCache := directives["no-cache"]
+			base := httpBaseKey(r, config.Query)
+
+			if !noStore && !noCache {
+				if entry := loadHTTPEntry(cache, o.codec, base, r); entry != nil {
+					writeHTTPEntry(w, r, entry, "HIT")
+					return
+				}
+			}
+
+			// HEAD responses have no body to serve later GET requests with
+			if r.Method == http.MethodHead {
+				w.Header().Set("X-Cache", "MISS")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &httpRecorder{
+				w:      w,
+				header: make(http.Header),
+				limit:  config.MaxBodySize,
+				decide: func(status int, header http.Header) (time.Duration, bool) {
+					if noStore {
+						return 0, false
+					}
+					return httpTTL(r, status, header, config)
+				},
+			}
+			next.ServeHTTP(recorder, r)
+			recorder.WriteHeader(http.StatusOK)
+			if !recorder.buffering {
+				return
+			}
+
+			entry := &httpEntry{
+				Status: recorder.status,
+				Header: recorder.header,
+				Body:   recorder.body.Bytes(),
+				Stored: time.Now(),
+			}
+
+			if entry.Header.Get("ETag") == "" {
+				sum := sha256.Sum256(entry.Body)
+				entry.Header.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+			}
+			storeHTTPEntry(cache, o.codec, base, r, entry, recorder.ttl)
+
+			writeHTTPEntry(w, r, entry, "MISS")
+		})
+	}
+}
+
+// httpRecorder buffers a cacheable response, deciding from its status and
+// headers when they are written. Other responses are streamed to w.
+type httpRecorder struct {
+	w      http.ResponseWriter
+	header http.Header
+	limit  int
+	decide func(status int, header http.Header) (time.Duration, bool)
+
+	status      int
+	wroteHeader bool
+	buffering   bool
+	ttl         time.Duration
+	body        bytes.Buffer
+}
+
+func (h *httpRecorder) Header() http.Header {
+	if h.wroteHeader && !h.buffering {
+		return h.w.Header()
+	}
+	return h.header
+}
+
+func (h *httpRecorder) WriteHeader(status int) {
+	if h.wroteHeader {
+		return
+	}
+
+	h.status = status
+	h.wroteHeader = true
+	h.ttl, h.buffering = h.decide(status, h.header)
+	if !h.buffering {
+		h.stream()
+	}
+}
+
+func (h *httpRecorder) Write(data []byte) (int, error) {
+	h.WriteHeader(http.StatusOK)
+	if h.buffering && h.body.Len()+len(data) > h.limit {
+		h.buffering = false
+		h.stream()
+	}
+
+	if h.buffering {
+		return h.body.Write(data)
+	}
+	return h.w.Write(data)
+}
+
+// Flush streams the response, which is no longer cached, and flushes it.
+func (h *httpRecorder) Flush() {
+	h.WriteHeader(http.StatusOK)
+	if h.buffering {
+		h.buffering = false
+		h.stream()
+	}
+
+	if flusher, ok := h.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (h *httpRecorder) Unwrap() http.ResponseWriter {
+	return h.w
+}
+
+// stream sends the status, headers and buffered body to the client.
+func (h *httpRecorder) stream() {
+	header := h.w.Header()
+	for name, values := range h.header {
+		header[name] = values
+	}
+	header.Set("X-Cache", "MISS")
+
+	h.w.WriteHeader(h.status)
+	if h.body.Len() > 0 {
+		_, _ = h.w.Write(h.body.Bytes())
+		h.body.Reset()
+	}
+}
+
+// httpBaseKey builds the cache key of a request without its Vary headers.
+// GET and HEAD requests share their key, as only GET responses are stored.
+func httpBaseKey(r *http.Request, params []string) string {
+	query := r.URL.Query()
+	if params != nil {
+		selected := make(url.Values)
+		for _, param := range params {
+			if values, ok := query[param]; ok {
+				selected[param] = values
+			}
+		}
+		query = selected
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + r.URL.Path + "?" + query.Encode()
+}
+
+// httpVaryKey builds the cache key of a request for the given Vary header names.
+func httpVaryKey(base string, r *http.Request, vary []string) string {
+	var sb strings.Builder
+	sb.WriteString(base)
+	for _, name := range vary {
+		sb.WriteString("\n" + name + ": " + strings.Join(r.Header.Values(name), ","))
+	}
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return "http:" + hex.EncodeToString(sum[:])
+}
+
+// httpVaryIndexKey returns the key storing the Vary header names of a base key.
+func httpVaryIndexKey(base string) string {
+	sum := sha256.Sum256([]byte(base))
+	return "http-vary:" + hex.EncodeToString(sum[:])
+}
+
+// loadHTTPEntry returns the cached response of a request, or nil.
+func loadHTTPEntry(cache Cache, codec Codec, base string, r *http.Request) *httpEntry {
+	index, exists, err := Lookup(cache, httpVaryIndexKey(base))
+	if err != nil || !exists {
+		return nil
+	}
+
+	vary := splitHeaderList(toText(index))
+	data, exists, err := Lookup(cache, httpVaryKey(base, r, vary))
+	if err != nil || !exists {
+		return nil
+	}
+
+	entry := new(httpEntry)
+	if err := codec.Unmarshal([]byte(toText(data)), entry); err != nil {
+		return nil
+	}
+
+	return entry
+}
+
+// storeHTTPEntry caches the response of a request along with its Vary header names.
+func storeHTTPEntry(cache Cache, codec Codec, base string, r *http.Request, entry *httpEntry, ttl time.Duration) {
+	data, err := codec.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	vary := splitHeaderList(strings.Join(entry.Header.Values("Vary"), ","))
+	for i, name := range vary {
+		vary[i] = http.CanonicalHeaderKey(name)
+	}
+	slices.Sort(vary)
+
+	if err := cache.Put(httpVaryIndexKey(base), strings.Join(vary, ","), &ttl); err != nil {
+		return
+	}
+
+	_ = cache.Put(httpVaryKey(base, r, vary), string(data), &ttl)
+}
+
+// writeHTTPEntry sends a response, answering matching conditional requests with 304.
+func writeHTTPEntry(w http.ResponseWriter, r *http.Request, entry *httpEntry, status string) {
+	header := w.Header()
+	for name, values := range entry.Header {
+		header[name] = slices.Clone(values)
+	}
+	header.Set("X-Cache", status)
+
+	if status == "HIT" {
+		age := max(time.Since(entry.Stored), 0)
+		header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	}
+
+	if etag := entry.Header.Get("ETag"); etag != "" && etagMatch(r.Header.Get("If-None-Match"), etag) {
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(entry.Status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(entry.Body)
+	}
+}
+
+// httpTTL returns the lifetime of a response, reporting false if it must not be cached.
+func httpTTL(r *http.Request, status int, header http.Header, config HTTPCacheConfig) (time.Duration, bool) {
+	if status != http.StatusOK {
+		return 0, false
+	}
+
+	if len(header.Values("Set-Cookie")) > 0 && !config.StoreCookies {
+		return 0, false
+	}
+
+	for _, name := range splitHeaderList(strings.Join(header.Values("Vary"), ",")) {
+		if name == "*" {
+			return 0, false
+		}
+	}
+
+	directives := parseCacheControl(strings.Join(header.Values("Cache-Control"), ","))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return 0, false
+		}
+	}
+
+	_, public := directives["public"]
+	if r.Header.Get("Authorization") != "" && !public {
+		return 0, false
+	}
+
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if value, ok := directives[directive]; ok {
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return config.TTL, config.TTL > 0
+}
+
+// parseCacheControl parses Cache-Control directives into a map of lower-case names.
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name != "" {
+			directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+	return directives
+}
+
+// splitHeaderList splits a comma-separated header value.
+func splitHeaderList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// etagMatch reports whether an If-None-Match header matches etag using weak comparison.
+func etagMatch(header, etag string) bool {
+	for _, candidate := range splitHeaderList(header) {
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// toText converts a cached string or byte slice to a string.
+func toText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,183 @@
+package cache_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache("http-"+strconv.FormatInt(time.Now().UnixNano(), 10), redis.NewClient(&redis.Options{})),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			handler := cache.NewHTTPMiddleware(c, cache.HTTPCacheConfig{
+				TTL:         time.Minute,
+				Query:       []string{"page"},
+				MaxBodySize: 64,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				switch r.URL.Path {
+				case "/private":
+					w.Header().Set("Cache-Control", "private")
+				case "/vary":
+					w.Header().Set("Vary", "Accept-Language")
+				case "/cookie":
+					http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+				case "/large":
+					fmt.Fprint(w, strings.Repeat("a", 100))
+				case "/stream":
+					fmt.Fprint(w, "event: start\n\n")
+					w.(http.Flusher).Flush()
+					assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+				}
+				fmt.Fprintf(w, "%s %s %s", r.URL.Path, r.URL.Query().Get("page"), r.Header.Get("Accept-Language"))
+			}))
+
+			serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(http.MethodGet, path, nil)
+				for k, v := range header {
+					r.Header[k] = v
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+				return w
+			}
+
+			t.Run("Hit and miss", func(t *testing.T) {
+				calls = 0
+				first := serve("/items-"+name+"?page=1&tracking=a", nil)
+				assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+				assert.NotEmpty(t, first.Header().Get("ETag"))
+
+				second := serve("/items-"+name+"?tracking=b&page=1", nil)
+				assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+				assert.Equal(t, first.Body.String(), second.Body.String())
+				assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+
+				serve("/items-"+name+"?page=2", nil)
+				assert.Equal(t, 2, calls)
+
+				bypass := serve("/items-"+name+"?page=1", http.Header{"Cache-Control": {"no-cache"}})
+				assert.Equal(t, "MISS", bypass.Header().Get("X-Cache"))
+			})
+
+			t.Run("If-None-Match", func(t *testing.T) {
+				first := serve("/etag-"+name, nil)
+				etag := first.Header().Get("ETag")
+
+				res := serve("/etag-"+name, http.Header{"If-None-Match": {etag}})
+				assert.Equal(t, http.StatusNotModified, res.Code)
+				assert.Empty(t, res.Body.String())
+
+				res = serve("/etag-"+name, http.Header{"If-None-Match": {`"other"`}})
+				assert.Equal(t, http.StatusOK, res.Code)
+			})
+
+			t.Run("Cache-Control", func(t *testing.T) {
+				calls = 0
+				serve("/private", nil)
+				res := serve("/private", nil)
+				assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+				assert.Equal(t, 2, calls)
+
+				serve("/auth-"+name, http.Header{"Authorization": {"Bearer token"}})
+				res = serve("/auth-"+name, http.Header{"Authorization": {"Bearer token"}})
+				assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+			})
+
+			t.Run("Set-Cookie", func(t *testing.T) {
+				calls = 0
+				serve("/cookie", nil)
+				res := serve("/cookie", nil)
+				assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+				assert.NotEmpty(t, res.Header().Get("Set-Cookie"))
+				assert.Equal(t, 2, calls)
+			})
+
+			t.Run("Streamed responses", func(t *testing.T) {
+				for _, path := range []string{"/large", "/stream"} {
+					calls = 0
+					first := serve(path, nil)
+					res := serve(path, nil)
+					assert.Equal(t, "MISS", res.Header().Get("X-Cache"), path)
+					assert.Equal(t, first.Body.String(), res.Body.String(), path)
+					assert.Contains(t, res.Body.String(), path, path)
+					assert.Equal(t, 2, calls, path)
+				}
+
+				res := serve("/stream", nil)
+				assert.True(t, res.Flushed)
+			})
+
+			t.Run("Host and HEAD", func(t *testing.T) {
+				request := func(method, host string) *httptest.ResponseRecorder {
+					r := httptest.NewRequest(method, "/hosts-"+name, nil)
+					r.Host = host
+					w := httptest.NewRecorder()
+					handler.ServeHTTP(w, r)
+					return w
+				}
+
+				// HEAD misses are not stored
+				calls = 0
+				res := request(http.MethodHead, "a.example")
+				assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+				res = request(http.MethodGet, "a.example")
+				assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+				assert.Contains(t, res.Body.String(), "/hosts-"+name)
+				assert.Equal(t, 2, calls)
+
+				// HEAD hits reuse the GET response without its body
+				res = request(http.MethodHead, "a.example")
+				assert.Equal(t, "HIT", res.Header().Get("X-Cache"))
+				assert.Empty(t, res.Body.String())
+
+				// Hosts are cached apart
+				res = request(http.MethodGet, "b.example")
+				assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+				assert.Equal(t, 3, calls)
+			})
+
+			t.Run("Vary", func(t *testing.T) {
+				en := serve("/vary", http.Header{"Accept-Language": {"en"}})
+				fa := serve("/vary", http.Header{"Accept-Language": {"fa"}})
+				assert.NotEqual(t, en.Body.String(), fa.Body.String())
+
+				res := serve("/vary", http.Header{"Accept-Language": {"fa"}})
+				require.Equal(t, "HIT", res.Header().Get("X-Cache"))
+				assert.Equal(t, fa.Body.String(), res.Body.String())
+			})
+		})
+	}
+
+	t.Run("Store cookies", func(t *testing.T) {
+		handler := cache.NewHTTPMiddleware(cache.NewMemoryCache(), cache.HTTPCacheConfig{
+			TTL:          time.Minute,
+			StoreCookies: true,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+			fmt.Fprint(w, "body")
+		}))
+
+		for _, status := range []string{"MISS", "HIT"} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, status, w.Header().Get("X-Cache"))
+			assert.Equal(t, "session=secret", w.Header().Get("Set-Cookie"))
+		}
+	})
+}
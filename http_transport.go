@@ -0,0 +1,325 @@
+package cache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpStaleRetention is how long stale responses with validators or stale-if-error
+// are kept after expiring, to be revalidated or served on errors.
+const httpStaleRetention = 24 * time.Hour
+
+// httpCacheableStatus lists the status codes cacheable by default (RFC 9110 section 15.1).
+var httpCacheableStatus = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+// httpTransport is a caching http.RoundTripper acting as a private cache.
+type httpTransport struct {
+	cache Cache
+	next  http.RoundTripper
+	opt   option
+}
+
+// NewHTTPTransport creates an http.RoundTripper caching GET responses of next in cache
+// as a private cache following RFC 9111. If next is nil, http.DefaultTransport is used.
+//
+// Fresh responses, as defined by max-age, Expires or the Last-Modified heuristic,
+// are served from the cache. Responses are streamed to the caller and stored once
+// their body is read to the end, bodies over 10 MiB are not stored. Stale responses are revalidated with If-None-Match and
+// If-Modified-Since, and served when the origin fails within their stale-if-error window.
+// Requests sending no-cache revalidate, requests sending no-store and conditional
+// requests bypass the cache, and successful unsafe requests invalidate their URL.
+// Entries are encoded with the codec set with WithCodec.
+func NewHTTPTransport(cache Cache, next http.RoundTripper, opts ...Option) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &httpTransport{
+		cache: cache,
+		next:  next,
+		opt:   newOption(opts...),
+	}
+}
+
+func (t *httpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := httpTransportKey(req.URL)
+	if req.Method != http.MethodGet {
+		res, err := t.next.RoundTrip(req)
+		if err == nil && req.Method != http.MethodHead && req.Method != http.MethodOptions && res.StatusCode < 400 {
+			_ = t.cache.Forget(httpVaryIndexKey(base))
+		}
+		return res, err
+	}
+
+	directives := parseCacheControl(req.Header.Get("Cache-Control"))
+	if _, noStore := directives["no-store"]; noStore || isConditional(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	entry := loadHTTPEntry(t.cache, t.opt.codec, base, req)
+	outgoing := req
+	var age, lifetime time.Duration
+	if entry != nil {
+		age = time.Since(entry.Stored)
+		lifetime = httpFreshness(entry)
+		if httpFresh(directives, age, lifetime) {
+			return httpResponse(req, entry, age, "HIT"), nil
+		}
+
+		outgoing = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outgoing.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			outgoing.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	res, err := t.next.RoundTrip(outgoing)
+	if entry != nil && (err != nil || res.StatusCode >= 500) && httpStaleIfError(entry, directives, age-lifetime) {
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		return httpResponse(req, entry, age, "STALE"), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		for name, values := range res.Header {
+			entry.Header[name] = values
+		}
+		entry.Stored = httpStored(res.Header)
+		t.store(base, req, entry)
+		return httpResponse(req, entry, 0, "REVALIDATED"), nil
+	}
+
+	entry = &httpEntry{
+		Status: res.StatusCode,
+		Header: res.Header,
+		Stored: httpStored(res.Header),
+	}
+	if !httpStorable(entry) || res.ContentLength > httpMaxBody {
+		return res, nil
+	}
+
+	res.Body = &httpCachingBody{
+		body:  res.Body,
+		limit: httpMaxBody,
+		store: func(body []byte) {
+			entry.Body = body
+			t.store(base, req, entry)
+		},
+	}
+	return res, nil
+}
+
+// httpCachingBody streams a response body while copying it, storing the copy
+// once the body is read to the end. Bodies closed early, failing or larger
+// than limit are not stored.
+type httpCachingBody struct {
+	body   io.ReadCloser
+	limit  int
+	store  func(body []byte)
+	buffer bytes.Buffer
+	done   bool
+}
+
+func (b *httpCachingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if !b.done {
+		if b.buffer.Len()+n > b.limit {
+			b.done = true
+			b.buffer = bytes.Buffer{}
+		} else {
+			b.buffer.Write(p[:n])
+		}
+	}
+
+	if err != nil && !b.done {
+		b.done = true
+		if err == io.EOF {
+			b.store(b.buffer.Bytes())
+		}
+		b.buffer = bytes.Buffer{}
+	}
+
+	return n, err
+}
+
+func (b *httpCachingBody) Close() error {
+	b.done = true
+	b.buffer = bytes.Buffer{}
+	return b.body.Close()
+}
+
+// store caches a response for its freshness lifetime and stale retention.
+func (t *httpTransport) store(base string, req *http.Request, entry *httpEntry) {
+	ttl := max(httpFreshness(entry), 0)
+	directives := parseCacheControl(strings.Join(entry.Header.Values("Cache-Control"), ","))
+	_, staleIfError := directives["stale-if-error"]
+	if staleIfError || entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != "" {
+		ttl += max(httpStaleRetention, httpSeconds(directives["stale-if-error"]))
+	}
+
+	if ttl > 0 {
+		storeHTTPEntry(t.cache, t.opt.codec, base, req, entry, ttl)
+	}
+}
+
+// httpTransportKey builds the cache key of a URL fetched by the transport.
+func httpTransportKey(u *url.URL) string {
+	return "transport GET " + u.String()
+}
+
+// isConditional reports whether a request carries its own validators.
+func isConditional(req *http.Request) bool {
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "If-Range"} {
+		if req.Header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// httpStored returns when a response was generated, accounting for its Age header.
+func httpStored(header http.Header) time.Time {
+	return time.Now().Add(-httpSeconds(header.Get("Age")))
+}
+
+// httpFreshness returns the freshness lifetime of a response (RFC 9111 section 4.2.1).
+func httpFreshness(entry *httpEntry) time.Duration {
+	directives := parseCacheControl(strings.Join(entry.Header.Values("Cache-Control"), ","))
+	if _, noCache := directives["no-cache"]; noCache {
+		return 0
+	}
+
+	if value, ok := directives["max-age"]; ok {
+		return httpSeconds(value)
+	}
+
+	date, err := http.ParseTime(entry.Header.Get("Date"))
+	if err != nil {
+		date = entry.Stored
+	}
+
+	if value := entry.Header.Get("Expires"); value != "" {
+		expires, err := http.ParseTime(value)
+		if err != nil {
+			return 0
+		}
+		return expires.Sub(date)
+	}
+
+	// Heuristic freshness of 10% of the time since last modification
+	if value := entry.Header.Get("Last-Modified"); value != "" && slices.Contains(httpCacheableStatus, entry.Status) {
+		if modified, err := http.ParseTime(value); err == nil {
+			return min(date.Sub(modified)/10, httpStaleRetention)
+		}
+	}
+
+	return 0
+}
+
+// httpFresh reports whether a response of the given age can be served without
+// revalidation according to the request directives.
+func httpFresh(directives map[string]string, age, lifetime time.Duration) bool {
+	if _, noCache := directives["no-cache"]; noCache {
+		return false
+	}
+
+	if value, ok := directives["max-age"]; ok && age > httpSeconds(value) {
+		return false
+	}
+
+	if value, ok := directives["min-fresh"]; ok {
+		lifetime -= httpSeconds(value)
+	}
+
+	return age < lifetime
+}
+
+// httpStaleIfError reports whether a response stale for the given duration
+// may be served when the origin fails (RFC 5861 section 4).
+func httpStaleIfError(entry *httpEntry, request map[string]string, staleness time.Duration) bool {
+	response := parseCacheControl(strings.Join(entry.Header.Values("Cache-Control"), ","))
+	if _, ok := response["must-revalidate"]; ok {
+		return false
+	}
+
+	for _, directives := range []map[string]string{request, response} {
+		if value, ok := directives["stale-if-error"]; ok && staleness <= httpSeconds(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpStorable reports whether a response may be stored (RFC 9111 section 3).
+func httpStorable(entry *httpEntry) bool {
+	if !slices.Contains(httpCacheableStatus, entry.Status) {
+		return false
+	}
+
+	directives := parseCacheControl(strings.Join(entry.Header.Values("Cache-Control"), ","))
+	if _, noStore := directives["no-store"]; noStore {
+		return false
+	}
+
+	if slices.Contains(splitHeaderList(strings.Join(entry.Header.Values("Vary"), ",")), "*") {
+		return false
+	}
+
+	_, staleIfError := directives["stale-if-error"]
+	return staleIfError || httpFreshness(entry) > 0 || entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != ""
+}
+
+// httpResponse builds a response to req from a cached entry.
+func httpResponse(req *http.Request, entry *httpEntry, age time.Duration, status string) *http.Response {
+	header := entry.Header.Clone()
+	header.Set("Age", strconv.FormatInt(int64(max(age, 0)/time.Second), 10))
+	header.Set("X-Cache", status)
+
+	return &http.Response{
+		Status:        strconv.Itoa(entry.Status) + " " + http.StatusText(entry.Status),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+// httpSeconds parses a delta-seconds value, returning zero for invalid values.
+func httpSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
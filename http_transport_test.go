@@ -0,0 +1,141 @@
+package cache_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPTransport(t *testing.T) {
+	var hits, revalidations atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidations.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/stale":
+			w.Header().Set("Cache-Control", "max-age=0, stale-if-error=60")
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+		case "/stream":
+			w.Header().Set("Cache-Control", "max-age=60")
+			io.WriteString(w, "first ")
+			w.(http.Flusher).Flush()
+		}
+		io.WriteString(w, "body of "+r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: cache.NewHTTPTransport(cache.NewMemoryCache(), nil)}
+	get := func(path string, header http.Header) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, string(body)
+	}
+
+	t.Run("Fresh", func(t *testing.T) {
+		hits.Store(0)
+		_, first := get("/fresh", nil)
+		res, second := get("/fresh", nil)
+		assert.Equal(t, first, second)
+		assert.Equal(t, "HIT", res.Header.Get("X-Cache"))
+		assert.EqualValues(t, 1, hits.Load())
+
+		res, _ = get("/fresh", http.Header{"Cache-Control": {"no-cache"}})
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 2, hits.Load())
+	})
+
+	t.Run("Revalidation", func(t *testing.T) {
+		get("/etag", nil)
+		res, body := get("/etag", nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "REVALIDATED", res.Header.Get("X-Cache"))
+		assert.Equal(t, "body of /etag", body)
+		assert.EqualValues(t, 1, revalidations.Load())
+	})
+
+	t.Run("Stale if error", func(t *testing.T) {
+		get("/stale", nil)
+		failing.Store(true)
+		defer failing.Store(false)
+
+		res, body := get("/stale", nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "STALE", res.Header.Get("X-Cache"))
+		assert.Equal(t, "body of /stale", body)
+
+		res, _ = get("/fresh-failing", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	})
+
+	t.Run("No store", func(t *testing.T) {
+		hits.Store(0)
+		get("/no-store", nil)
+		get("/no-store", nil)
+		assert.EqualValues(t, 2, hits.Load())
+	})
+
+	t.Run("Streamed body", func(t *testing.T) {
+		hits.Store(0)
+
+		// A body closed before the end is not stored
+		res, err := client.Get(server.URL + "/stream")
+		require.NoError(t, err)
+		chunk := make([]byte, 6)
+		_, err = io.ReadFull(res.Body, chunk)
+		require.NoError(t, err)
+		assert.Equal(t, "first ", string(chunk))
+		res.Body.Close()
+
+		res, body := get("/stream", nil)
+		assert.Empty(t, res.Header.Get("X-Cache"))
+		assert.Equal(t, "first body of /stream", body)
+
+		res, body = get("/stream", nil)
+		assert.Equal(t, "HIT", res.Header.Get("X-Cache"))
+		assert.Equal(t, "first body of /stream", body)
+		assert.EqualValues(t, 2, hits.Load())
+	})
+
+	t.Run("Invalidation", func(t *testing.T) {
+		hits.Store(0)
+		get("/fresh", nil)
+		assert.EqualValues(t, 0, hits.Load())
+
+		res, err := client.Post(server.URL+"/fresh", "text/plain", nil)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		get("/fresh", nil)
+		assert.EqualValues(t, 2, hits.Load())
+	})
+}
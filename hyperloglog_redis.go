@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisHyperLogLog is a Redis-based implementation of the HyperLogLog interface.
+type redisHyperLogLog struct {
+	name   string
+	key    string
+	client redis.UniversalClient
+	opt    option
+}
+
+// NewRedisHyperLogLog creates a new Redis HyperLogLog counter with a given name and Redis client.
+func NewRedisHyperLogLog(name string, client redis.UniversalClient, opts ...Option) HyperLogLog {
+	o := newOption(opts...)
+	return &redisHyperLogLog{
+		name:   name,
+		key:    hashTag(o.keyEncoder.Encode("hyperloglog", name)),
+		client: client,
+		opt:    o,
+	}
+}
+
+func (r *redisHyperLogLog) Add(items ...string) (err error) {
+	defer r.finish("hll_add", time.Now(), &err)
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(items))
+	for _, item := range items {
+		args = append(args, item)
+	}
+
+	return r.client.PFAdd(context.Background(), r.key, args...).Err()
+}
+
+func (r *redisHyperLogLog) Count() (_ uint64, err error) {
+	defer r.finish("hll_count", time.Now(), &err)
+
+	count, err := r.client.PFCount(context.Background(), r.key).Result()
+	return uint64(count), err
+}
+
+// Merge merges others with PFMERGE. On clusters, where the counters may live
+// in other slots, each one is first copied next to this counter.
+func (r *redisHyperLogLog) Merge(others ...HyperLogLog) (err error) {
+	defer r.finish("hll_merge", time.Now(), &err)
+
+	keys := make([]string, 0, len(others))
+	for _, other := range others {
+		o, ok := other.(*redisHyperLogLog)
+		if !ok {
+			return &OpError{Op: "hll_merge", Key: r.name, Err: ErrWrongType}
+		}
+		keys = append(keys, o.key)
+	}
+
+	if _, ok := r.client.(*redis.ClusterClient); !ok {
+		return r.client.PFMerge(context.Background(), r.key, keys...).Err()
+	}
+
+	for _, key := range keys {
+		if err := r.mergeCopy(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *redisHyperLogLog) Clear() (err error) {
+	defer r.finish("hll_clear", time.Now(), &err)
+
+	return r.client.Del(context.Background(), r.key).Err()
+}
+
+// mergeCopy merges the counter at key through a temporary copy sharing the slot of this counter.
+func (r *redisHyperLogLog) mergeCopy(key string) error {
+	data, err := r.client.Get(context.Background(), key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	tmp := r.key + ":merge:" + token
+	_, err = r.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		pipe.Set(context.Background(), tmp, data, time.Minute)
+		pipe.PFMerge(context.Background(), r.key, tmp)
+		pipe.Del(context.Background(), tmp)
+		return nil
+	})
+	return err
+}
+
+// finish maps the error of a completed operation and logs it.
+func (r *redisHyperLogLog) finish(op string, start time.Time, err *error) {
+	*err = redisError(op, r.name, *err)
+	r.opt.observe(op, r.name, start, err)
+}
@@ -0,0 +1,66 @@
+package cache_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	client := redis.NewClient(&redis.Options{})
+	suffix := time.Now().Format(time.RFC3339Nano)
+	factories := map[string]func(name string) cache.HyperLogLog{
+		"memory": func(string) cache.HyperLogLog {
+			return cache.NewMemoryHyperLogLog()
+		},
+		"redis": func(name string) cache.HyperLogLog {
+			return cache.NewRedisHyperLogLog(name+" "+suffix, client)
+		},
+	}
+
+	for name, newCounter := range factories {
+		t.Run(name, func(t *testing.T) {
+			monday := newCounter("monday")
+			tuesday := newCounter("tuesday")
+
+			count, err := monday.Count()
+			require.NoError(t, err)
+			assert.Zero(t, count)
+
+			for i := range 10000 {
+				require.NoError(t, monday.Add("visitor"+strconv.Itoa(i), "visitor"+strconv.Itoa(i/2)))
+			}
+			for i := 5000; i < 15000; i++ {
+				require.NoError(t, tuesday.Add("visitor"+strconv.Itoa(i)))
+			}
+
+			count, err = monday.Count()
+			require.NoError(t, err)
+			assert.InEpsilon(t, 10000, count, 0.03)
+
+			require.NoError(t, monday.Merge(tuesday))
+			count, err = monday.Count()
+			require.NoError(t, err)
+			assert.InEpsilon(t, 15000, count, 0.03)
+
+			count, err = tuesday.Count()
+			require.NoError(t, err)
+			assert.InEpsilon(t, 10000, count, 0.03)
+
+			require.NoError(t, monday.Clear())
+			count, err = monday.Count()
+			require.NoError(t, err)
+			assert.Zero(t, count)
+		})
+	}
+
+	t.Run("Merge type mismatch", func(t *testing.T) {
+		err := cache.NewMemoryHyperLogLog().Merge(cache.NewRedisHyperLogLog("mismatch", client))
+		assert.ErrorIs(t, err, cache.ErrWrongType)
+	})
+}
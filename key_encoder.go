@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// hashedKeyMarker starts the key part of keys hashed for length.
+// It is never produced by escaping, so hashed keys cannot collide with escaped ones.
+const hashedKeyMarker = "#"
+
+// KeyEncoder converts cache keys into backend keys.
+type KeyEncoder interface {
+	// Encode returns the backend key of key within the namespace prefix.
+	Encode(prefix, key string) string
+
+	// Decode returns the key of a backend key produced by Encode with the same prefix.
+	// It reports false if the key cannot be recovered, such as for hashed keys.
+	// With an empty prefix every backend key is accepted, including the keys of
+	// other namespaces, so callers enumerating keys must require a prefix.
+	Decode(prefix, encoded string) (string, bool)
+}
+
+var (
+	// DefaultKeyEncoder escapes unsafe characters and hashes keys longer than 200 bytes.
+	DefaultKeyEncoder = NewKeyEncoder(200)
+
+	// LegacyKeyEncoder strips every character outside [a-zA-Z0-9-] from keys,
+	// matching the keys of earlier versions. Distinct keys may collide.
+	LegacyKeyEncoder KeyEncoder = legacyKeyEncoder{}
+)
+
+// NewKeyEncoder creates a collision-free key encoder. Bytes outside [a-zA-Z0-9._-]
+// are percent-encoded and the prefix is joined with a colon. Encoded keys longer
+// than maxLength bytes use the SHA-256 hash of the key instead. Zero maxLength
+// disables hashing.
+func NewKeyEncoder(maxLength int) KeyEncoder {
+	return escapeKeyEncoder{maxLength: maxLength}
+}
+
+// escapeKeyEncoder is the percent-encoding implementation of the KeyEncoder interface.
+type escapeKeyEncoder struct {
+	maxLength int
+}
+
+func (e escapeKeyEncoder) Encode(prefix, key string) string {
+	encoded := joinKey(escapeKey(prefix), escapeKey(key))
+	if e.maxLength <= 0 || len(encoded) <= e.maxLength {
+		return encoded
+	}
+
+	return joinKey(escapeKey(prefix), hashKey(key))
+}
+
+func (e escapeKeyEncoder) Decode(prefix, encoded string) (string, bool) {
+	key, ok := splitKey(escapeKey(prefix), encoded)
+	if !ok || strings.HasPrefix(key, hashedKeyMarker) {
+		return "", false
+	}
+
+	key, err := url.PathUnescape(key)
+	return key, err == nil
+}
+
+// legacyKeyEncoder is the slug implementation of the KeyEncoder interface.
+type legacyKeyEncoder struct{}
+
+func (legacyKeyEncoder) Encode(prefix, key string) string {
+	return cacheKey(prefix, key)
+}
+
+func (legacyKeyEncoder) Decode(prefix, encoded string) (string, bool) {
+	return splitKey(slugify(prefix), encoded)
+}
+
+// escapeKey percent-encodes the bytes of s outside [a-zA-Z0-9._-].
+func escapeKey(s string) string {
+	const digits = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.':
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(digits[c>>4])
+			sb.WriteByte(digits[c&15])
+		}
+	}
+	return sb.String()
+}
+
+// hashKey returns the hashed form of a key.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hashedKeyMarker + hex.EncodeToString(sum[:])
+}
+
+// joinKey joins an encoded prefix and key.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + ":" + key
+}
+
+// splitKey removes an encoded prefix from a backend key.
+func splitKey(prefix, encoded string) (string, bool) {
+	if prefix == "" {
+		return encoded, true
+	}
+
+	return strings.CutPrefix(encoded, prefix+":")
+}
@@ -0,0 +1,85 @@
+package cache_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyEncoder(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		encoder := cache.DefaultKeyEncoder
+		assert.Equal(t, "app:user.1", encoder.Encode("app", "user.1"))
+		assert.Equal(t, "app:user1", encoder.Encode("app", "user1"))
+		assert.Equal(t, "app:user%3A42", encoder.Encode("app", "user:42"))
+		assert.Equal(t, "user%2042", encoder.Encode("", "user 42"))
+		assert.NotEqual(t, "app:", encoder.Encode("app", "کاربر"))
+
+		for _, key := range []string{"user.1", "user:42", "کاربر", "a%b", ""} {
+			encoded := encoder.Encode("my app", key)
+			assert.True(t, strings.HasPrefix(encoded, "my%20app:"))
+
+			decoded, ok := encoder.Decode("my app", encoded)
+			assert.True(t, ok)
+			assert.Equal(t, key, decoded)
+		}
+
+		_, ok := encoder.Decode("app", "other:key")
+		assert.False(t, ok)
+	})
+
+	t.Run("Hashing", func(t *testing.T) {
+		encoder := cache.NewKeyEncoder(32)
+		long := strings.Repeat("k", 64)
+
+		encoded := encoder.Encode("app", long)
+		assert.True(t, strings.HasPrefix(encoded, "app:#"))
+		assert.NotEqual(t, encoded, encoder.Encode("app", long+"x"))
+		assert.Equal(t, encoded, encoder.Encode("app", long))
+
+		_, ok := encoder.Decode("app", encoded)
+		assert.False(t, ok)
+
+		assert.Equal(t, "app:short", encoder.Encode("app", "short"))
+	})
+
+	t.Run("Legacy", func(t *testing.T) {
+		encoder := cache.LegacyKeyEncoder
+		assert.Equal(t, "app:user1", encoder.Encode("app", "user.1"))
+		assert.Equal(t, encoder.Encode("app", "user1"), encoder.Encode("app", "user.1"))
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{})
+		prefix := "encoder " + time.Now().Format(time.RFC3339Nano)
+
+		c := cache.NewRedisCache(prefix, client)
+		require.NoError(t, c.Put("user.1", "dotted", nil))
+		require.NoError(t, c.Put("user1", "plain", nil))
+		require.NoError(t, c.Put("کاربر", "persian", nil))
+
+		for key, expected := range map[string]string{"user.1": "dotted", "user1": "plain", "کاربر": "persian"} {
+			caster, err := c.Cast(key)
+			require.NoError(t, err)
+			assert.Equal(t, expected, caster.StringSafe(""))
+		}
+
+		legacy := cache.NewRedisCache(prefix, client, cache.WithKeyEncoder(cache.LegacyKeyEncoder))
+		require.NoError(t, legacy.Put("user.1", "dotted", nil))
+		caster, err := legacy.Cast("user1")
+		require.NoError(t, err)
+		assert.Equal(t, "dotted", caster.StringSafe(""))
+
+		// Without a prefix the keys of other namespaces would be enumerated
+		err = cache.NewRedisCache("", client).(cache.Enumerable).Range(func(key string) bool {
+			t.Errorf("unexpected key %q", key)
+			return true
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderElector defines the interface for electing a single active instance.
+type LeaderElector interface {
+	// Campaign blocks until this instance becomes the leader or ctx is done.
+	// Returns an error if the operation fails or ctx is done.
+	Campaign(ctx context.Context) error
+
+	// Resign gives up the leadership if held.
+	// Returns an error if the operation fails.
+	Resign() error
+
+	// IsLeader reports whether this instance currently holds the leadership.
+	// The leadership is given up as soon as the lease may have expired.
+	IsLeader() bool
+
+	// Changes returns a channel receiving true when the leadership is gained
+	// and false when it is resigned or lost. Unread changes are coalesced,
+	// so the channel always delivers the latest state.
+	Changes() <-chan bool
+}
+
+// leaderElector is the concrete implementation of the LeaderElector interface.
+// The leadership is a lease held through an auto-renewed lock.
+type leaderElector struct {
+	lock    *lock
+	changes chan bool
+
+	mutex  sync.Mutex
+	leader bool
+}
+
+// NewRedisLeaderElector creates a new leader elector backed by a Redis lease.
+// The lease expires after ttl if the leader stops renewing it.
+func NewRedisLeaderElector(name string, ttl time.Duration, client redis.UniversalClient, opts ...Option) LeaderElector {
+	name = "leader " + name
+	return newLeaderElector(newLock(name, ttl, newRedisLockBackend(name, client, newOption(opts...).keyEncoder), withLeaderOptions(opts)...))
+}
+
+// NewMemoryLeaderElector creates a new in-memory leader elector.
+// Electors with the same name compete within the process.
+func NewMemoryLeaderElector(name string, ttl time.Duration, opts ...Option) LeaderElector {
+	name = "leader " + name
+	return newLeaderElector(newLock(name, ttl, &memLockBackend{name: name}, withLeaderOptions(opts)...))
+}
+
+// newLeaderElector creates a leader elector over the given lock.
+func newLeaderElector(l *lock) *leaderElector {
+	e := &leaderElector{
+		lock:    l,
+		changes: make(chan bool, 1),
+	}
+	l.onChange = e.set
+	l.onLost = e.lost
+	return e
+}
+
+// withLeaderOptions forces the lease renewal required by the elector.
+func withLeaderOptions(opts []Option) []Option {
+	return append(opts[:len(opts):len(opts)], WithAutoRenew())
+}
+
+func (e *leaderElector) Campaign(ctx context.Context) error {
+	return e.lock.Lock(ctx)
+}
+
+func (e *leaderElector) Resign() error {
+	_, err := e.lock.Unlock()
+	return err
+}
+
+func (e *leaderElector) IsLeader() bool {
+	return e.lock.Token() != 0
+}
+
+func (e *leaderElector) Changes() <-chan bool {
+	return e.changes
+}
+
+// lost logs a lease lost because its renewal failed.
+// The state is already updated through set when the lock is reset.
+func (e *leaderElector) lost() {
+	e.lock.opt.logger.Warn("leadership lost", slog.String("leader", e.lock.name))
+}
+
+// set updates the leadership state and publishes changes.
+// It is called by the lock with its mutex held, so the state follows
+// acquisitions and losses in the order they happen.
+func (e *leaderElector) set(leader bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.leader == leader {
+		return
+	}
+
+	e.leader = leader
+
+	// Replace an unread change, sends never block as they are serialized by the mutex
+	select {
+	case <-e.changes:
+	default:
+	}
+	e.changes <- leader
+}
@@ -0,0 +1,121 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElector(t *testing.T) {
+	client := redis.NewClient(&redis.Options{})
+	factories := map[string]func(name string, ttl time.Duration, opts ...cache.Option) cache.LeaderElector{
+		"memory": cache.NewMemoryLeaderElector,
+		"redis": func(name string, ttl time.Duration, opts ...cache.Option) cache.LeaderElector {
+			return cache.NewRedisLeaderElector(name, ttl, client, opts...)
+		},
+	}
+
+	for name, newElector := range factories {
+		t.Run(name, func(t *testing.T) {
+			first := newElector("test", time.Second)
+			second := newElector("test", time.Second, cache.WithRetry(5*time.Millisecond, 10*time.Millisecond))
+
+			err := first.Campaign(context.Background())
+			require.NoError(t, err)
+			assert.True(t, first.IsLeader())
+			assert.True(t, <-first.Changes())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err = second.Campaign(ctx)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.False(t, second.IsLeader())
+
+			err = first.Resign()
+			require.NoError(t, err)
+			assert.False(t, first.IsLeader())
+			assert.False(t, <-first.Changes())
+
+			err = second.Campaign(context.Background())
+			require.NoError(t, err)
+			assert.True(t, second.IsLeader())
+
+			err = second.Resign()
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("Coalesced changes", func(t *testing.T) {
+		elector := cache.NewMemoryLeaderElector("coalesced", time.Second)
+
+		require.NoError(t, elector.Campaign(context.Background()))
+		require.NoError(t, elector.Resign())
+		require.NoError(t, elector.Campaign(context.Background()))
+
+		// Only the latest state is delivered
+		assert.True(t, <-elector.Changes())
+		select {
+		case leader := <-elector.Changes():
+			t.Fatalf("unexpected change %v", leader)
+		default:
+		}
+
+		require.NoError(t, elector.Resign())
+		assert.False(t, <-elector.Changes())
+	})
+
+	t.Run("Lease expiry", func(t *testing.T) {
+		limiter := &failingLimiter{}
+		failing := redis.NewClient(&redis.Options{Limiter: limiter})
+		elector := cache.NewRedisLeaderElector(uniquePrefix("expiry"), 300*time.Millisecond, failing)
+
+		require.NoError(t, elector.Campaign(context.Background()))
+		assert.True(t, <-elector.Changes())
+
+		// The leadership is given up before the lease expires on an unreachable backend
+		limiter.failing.Store(true)
+		failed := time.Now()
+		select {
+		case leader := <-elector.Changes():
+			assert.False(t, leader)
+		case <-time.After(time.Second):
+			t.Fatal("lease expiry not detected")
+		}
+		assert.Less(t, time.Since(failed), 300*time.Millisecond)
+		assert.False(t, elector.IsLeader())
+	})
+
+	t.Run("Lease loss", func(t *testing.T) {
+		elector := cache.NewRedisLeaderElector("loss", 60*time.Millisecond, client)
+
+		err := elector.Campaign(context.Background())
+		require.NoError(t, err)
+		assert.True(t, <-elector.Changes())
+
+		// Simulate the lease being taken away
+		err = client.Del(context.Background(), "{lock:leader%20loss}").Err()
+		require.NoError(t, err)
+
+		select {
+		case leader := <-elector.Changes():
+			assert.False(t, leader)
+		case <-time.After(time.Second):
+			t.Fatal("lease loss not detected")
+		}
+		assert.False(t, elector.IsLeader())
+
+		err = elector.Campaign(context.Background())
+		require.NoError(t, err)
+		assert.True(t, elector.IsLeader())
+		assert.True(t, <-elector.Changes())
+
+		err = elector.Resign()
+		require.NoError(t, err)
+		assert.False(t, elector.IsLeader())
+	})
+}
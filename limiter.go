@@ -1,6 +1,10 @@
 package cache
 
-import "time"
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
 
 // RateLimiter defines the interface for a rate limiter.
 type RateLimiter interface {
@@ -43,50 +47,79 @@ type limiter struct {
 	maxAttempts uint32
 	ttl         time.Duration
 	cache       Cache
+	opt         option
+	locked      atomic.Bool
 }
 
 // NewRateLimiter creates and returns a new rate limiter instance.
-func NewRateLimiter(name string, maxAttempts uint32, ttl time.Duration, cache Cache) RateLimiter {
+func NewRateLimiter(name string, maxAttempts uint32, ttl time.Duration, cache Cache, opts ...Option) RateLimiter {
 	return &limiter{
 		name:        "limiter " + name,
 		maxAttempts: maxAttempts,
 		ttl:         ttl,
 		cache:       cache,
+		opt:         newOption(opts...),
 	}
 }
 
-func (l *limiter) Hit() error {
+func (l *limiter) Hit() (err error) {
+	defer l.opt.observe("hit", l.name, time.Now(), &err)
+
 	exists, err := l.cache.Decrement(l.name, 1)
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		return l.cache.Put(l.name, l.maxAttempts-1, &l.ttl)
+		err = l.cache.Put(l.name, l.maxAttempts-1, &l.ttl)
+		if err != nil {
+			return err
+		}
+		l.reportLockout(l.maxAttempts <= 1)
 	}
 
 	return nil
 }
 
-func (l *limiter) Lock() error {
+func (l *limiter) Lock() (err error) {
+	defer l.opt.observe("lock", l.name, time.Now(), &err)
+
 	exists, err := l.cache.Update(l.name, 0)
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		return l.cache.Put(l.name, 0, &l.ttl)
+		err = l.cache.Put(l.name, 0, &l.ttl)
+		if err != nil {
+			return err
+		}
 	}
 
+	l.reportLockout(true)
 	return nil
 }
 
-func (l *limiter) Reset() error {
-	return l.cache.Put(l.name, l.maxAttempts, &l.ttl)
+func (l *limiter) Reset() (err error) {
+	defer l.opt.observe("reset", l.name, time.Now(), &err)
+
+	if err := l.cache.Put(l.name, l.maxAttempts, &l.ttl); err != nil {
+		return err
+	}
+
+	l.reportLockout(l.maxAttempts == 0)
+	return nil
 }
 
-func (l *limiter) Clear() error {
-	return l.cache.Forget(l.name)
+func (l *limiter) Clear() (err error) {
+	defer l.opt.observe("clear", l.name, time.Now(), &err)
+
+	if err := l.cache.Forget(l.name); err != nil {
+		return err
+	}
+
+	l.reportLockout(false)
+	return nil
 }
 
 func (l *limiter) MustLock() (bool, error) {
@@ -96,6 +129,7 @@ func (l *limiter) MustLock() (bool, error) {
 	}
 
 	if caster.IsNil() {
+		l.reportLockout(false)
 		return false, nil
 	}
 
@@ -104,6 +138,7 @@ func (l *limiter) MustLock() (bool, error) {
 		return true, err
 	}
 
+	l.reportLockout(num <= 0)
 	return num <= 0, nil
 }
 
@@ -122,10 +157,7 @@ func (l *limiter) TotalAttempts() (uint32, error) {
 		return 0, err
 	}
 
-	if num < int(l.maxAttempts) {
-		num = int(l.maxAttempts)
-	}
-
+	num = max(min(num, int(l.maxAttempts)), 0)
 	return l.maxAttempts - uint32(num), nil
 }
 
@@ -159,3 +191,18 @@ func (l *limiter) AvailableIn() (time.Duration, error) {
 
 	return ttl, nil
 }
+
+// reportLockout records the lock state seen by an operation and logs a warning
+// when it changes to locked. Decrements by Hit are only seen by later reads.
+func (l *limiter) reportLockout(locked bool) {
+	if l.locked.Swap(locked) || !locked {
+		return
+	}
+
+	l.opt.logger.Warn(
+		"rate limiter locked",
+		slog.String("limiter", l.name),
+		slog.Uint64("max_attempts", uint64(l.maxAttempts)),
+		slog.Duration("ttl", l.ttl),
+	)
+}
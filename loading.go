@@ -0,0 +1,335 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Loaded is a value fetched by a Loader with the TTL to cache it for.
+// A nil TTL caches the value indefinitely.
+type Loaded struct {
+	Value any
+	TTL   *time.Duration
+}
+
+// Loader fetches values missing from a LoadingCache.
+type Loader interface {
+	// Load fetches the value of key.
+	Load(ctx context.Context, key string) (Loaded, error)
+
+	// LoadMany fetches the values of keys in a single batch.
+	// Keys missing from the result are reported as not found.
+	LoadMany(ctx context.Context, keys []string) (map[string]Loaded, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface.
+// Batches are loaded one key at a time, skipping keys reported with ErrNotFound.
+type LoaderFunc func(ctx context.Context, key string) (Loaded, error)
+
+func (f LoaderFunc) Load(ctx context.Context, key string) (Loaded, error) {
+	return f(ctx, key)
+}
+
+func (f LoaderFunc) LoadMany(ctx context.Context, keys []string) (map[string]Loaded, error) {
+	result := make(map[string]Loaded, len(keys))
+	for _, key := range keys {
+		loaded, err := f(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = loaded
+	}
+
+	return result, nil
+}
+
+// LoadingCache is a Cache that loads missing values through a Loader.
+type LoadingCache interface {
+	Cache
+
+	// Load returns the value of key, loading and caching it on a miss.
+	// Concurrent loads of the same key share a single loader call, which is not
+	// cancelled when ctx is done; Load then returns ctx.Err() while the value is
+	// still loaded and cached. A panicking loader fails the load with an error.
+	Load(ctx context.Context, key string) (any, error)
+
+	// LoadMany returns the values of keys, loading the missing ones in a single batch.
+	// Keys the loader does not return are missing from the result.
+	LoadMany(ctx context.Context, keys []string) (map[string]any, error)
+}
+
+// loadingCache is a read-through Cache decorator.
+type loadingCache struct {
+	Cache
+	loader  Loader
+	opt     option
+	flights flightGroup
+
+	mutex    sync.Mutex
+	loadedAt map[string]loadStamp
+	pruned   int
+}
+
+// loadStamp records when a value was written and when it expires, nil for no expiry.
+type loadStamp struct {
+	at     time.Time
+	expiry *time.Time
+}
+
+// NewLoadingCache creates a read-through cache over cache loading misses with loader.
+// With WithRefreshAfter, values older than the given duration are returned as is
+// and reloaded in the background. With WithLoadTimeout, loader calls are abandoned
+// after the given duration with context.DeadlineExceeded.
+func NewLoadingCache(cache Cache, loader Loader, opts ...Option) LoadingCache {
+	return &loadingCache{
+		Cache:    cache,
+		loader:   loader,
+		opt:      newOption(opts...),
+		loadedAt: make(map[string]loadStamp),
+	}
+}
+
+func (l *loadingCache) Load(ctx context.Context, key string) (any, error) {
+	val, exists, err := Lookup(l.Cache, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		l.refresh(key)
+		return val, nil
+	}
+
+	l.forget(key)
+	return l.flights.do(ctx, key, func(ctx context.Context) (any, error) {
+		return l.load(ctx, key)
+	})
+}
+
+func (l *loadingCache) LoadMany(ctx context.Context, keys []string) (_ map[string]any, err error) {
+	result := make(map[string]any, len(keys))
+	var misses []string
+	for _, key := range keys {
+		if _, done := result[key]; done || slices.Contains(misses, key) {
+			continue
+		}
+
+		val, exists, err := Lookup(l.Cache, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			l.refresh(key)
+			result[key] = val
+		} else {
+			l.forget(key)
+			misses = append(misses, key)
+		}
+	}
+
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	defer l.opt.observe("load_many", misses[0], time.Now(), &err)
+
+	var loaded map[string]Loaded
+	err = l.call(ctx, func(ctx context.Context) (err error) {
+		loaded, err = l.loader.LoadMany(ctx, misses)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range misses {
+		item, ok := loaded[key]
+		if !ok {
+			continue
+		}
+
+		if err := l.store(key, item); err != nil {
+			return nil, err
+		}
+		result[key] = item.Value
+	}
+
+	return result, nil
+}
+
+func (l *loadingCache) Put(key string, value any, ttl *time.Duration) error {
+	err := l.Cache.Put(key, value, ttl)
+	if err == nil {
+		l.touch(key, ttl)
+	}
+	return err
+}
+
+func (l *loadingCache) Lookup(key string) (any, bool, error) {
+	return Lookup(l.Cache, key)
+}
+
+func (l *loadingCache) Pull(key string) (any, error) {
+	l.forget(key)
+	return l.Cache.Pull(key)
+}
+
+func (l *loadingCache) Forget(key string) error {
+	l.forget(key)
+	return l.Cache.Forget(key)
+}
+
+// load fetches and stores the value of key.
+func (l *loadingCache) load(ctx context.Context, key string) (_ any, err error) {
+	defer l.opt.observe("load", key, time.Now(), &err)
+
+	var loaded Loaded
+	err = l.call(ctx, func(ctx context.Context) (err error) {
+		loaded, err = l.loader.Load(ctx, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.store(key, loaded); err != nil {
+		return nil, err
+	}
+
+	return loaded.Value, nil
+}
+
+// store caches a loaded value and records when it was loaded.
+func (l *loadingCache) store(key string, loaded Loaded) error {
+	if err := l.Cache.Put(key, loaded.Value, loaded.TTL); err != nil {
+		return err
+	}
+
+	l.touch(key, loaded.TTL)
+	return nil
+}
+
+// touch records that the value of key was written now with the given ttl.
+func (l *loadingCache) touch(key string, ttl *time.Duration) {
+	if l.opt.refreshAfter <= 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.stamp(key, ttl)
+}
+
+// forget drops the load time of a value removed or found missing.
+func (l *loadingCache) forget(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.loadedAt, key)
+}
+
+// stamp records the load time of key and prunes the load times of expired values
+// whenever their number has doubled since the last prune, so that the map follows
+// the number of live keys. It must be called with the mutex held.
+func (l *loadingCache) stamp(key string, ttl *time.Duration) {
+	now := time.Now()
+	stamp := loadStamp{at: now}
+	if ttl != nil && *ttl > 0 {
+		expiry := now.Add(*ttl)
+		stamp.expiry = &expiry
+	}
+	l.loadedAt[key] = stamp
+
+	if len(l.loadedAt) < 2*max(l.pruned, 64) {
+		return
+	}
+
+	for key, stamp := range l.loadedAt {
+		if stamp.expiry != nil && !now.Before(*stamp.expiry) {
+			delete(l.loadedAt, key)
+		}
+	}
+	l.pruned = len(l.loadedAt)
+}
+
+// refresh reloads key in the background when its value is older than the refresh age.
+// Values not written by this cache are aged from the first time they are read.
+func (l *loadingCache) refresh(key string) {
+	if l.opt.refreshAfter <= 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	stamp, ok := l.loadedAt[key]
+	l.mutex.Unlock()
+
+	if !ok {
+		l.first(key)
+		return
+	}
+
+	if time.Since(stamp.at) < l.opt.refreshAfter || l.flights.busy(key) {
+		return
+	}
+
+	go func() {
+		_, err := l.flights.do(context.Background(), key, func(ctx context.Context) (any, error) {
+			return l.load(ctx, key)
+		})
+		if err != nil {
+			l.opt.logger.Warn(
+				"cache refresh failed",
+				slog.String("key", key),
+				slog.Any("error", err),
+			)
+		}
+	}()
+}
+
+// first starts aging a value not written by this cache, keeping its TTL
+// so that its load time is pruned once it expires.
+func (l *loadingCache) first(key string) {
+	ttl, exists, err := remainingTTL(l.Cache, key)
+	if err != nil || !exists {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.loadedAt[key]; !ok {
+		l.stamp(key, ttl)
+	}
+}
+
+// call runs fn with the load timeout, returning once the timeout passes
+// even if fn ignores its context.
+func (l *loadingCache) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if l.opt.loadTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, l.opt.loadTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
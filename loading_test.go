@@ -0,0 +1,183 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchLoader is a Loader counting its calls.
+type batchLoader struct {
+	loads   atomic.Int32
+	batches atomic.Int32
+	delay   time.Duration
+}
+
+func (b *batchLoader) Load(ctx context.Context, key string) (cache.Loaded, error) {
+	b.loads.Add(1)
+	time.Sleep(b.delay)
+	if key == "missing" {
+		return cache.Loaded{}, cache.ErrNotFound
+	}
+
+	ttl := time.Minute
+	return cache.Loaded{Value: "value of " + key, TTL: &ttl}, nil
+}
+
+func (b *batchLoader) LoadMany(ctx context.Context, keys []string) (map[string]cache.Loaded, error) {
+	b.batches.Add(1)
+	result := make(map[string]cache.Loaded)
+	for _, key := range keys {
+		if key != "missing" {
+			result[key] = cache.Loaded{Value: "value of " + key}
+		}
+	}
+	return result, nil
+}
+
+func TestLoadingCache(t *testing.T) {
+	t.Run("Load", func(t *testing.T) {
+		loader := &batchLoader{delay: 20 * time.Millisecond}
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), loader)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				val, err := c.Load(context.Background(), "key")
+				assert.NoError(t, err)
+				assert.Equal(t, "value of key", val)
+			}()
+		}
+		wg.Wait()
+		assert.EqualValues(t, 1, loader.loads.Load())
+
+		ttl, err := c.TTL("key")
+		require.NoError(t, err)
+		assert.Greater(t, ttl, 59*time.Second)
+
+		_, err = c.Load(context.Background(), "missing")
+		assert.ErrorIs(t, err, cache.ErrNotFound)
+
+		exists, err := c.Exists("missing")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("LoadMany", func(t *testing.T) {
+		loader := &batchLoader{}
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), loader)
+		require.NoError(t, c.Put("cached", "cached value", nil))
+
+		values, err := c.LoadMany(context.Background(), []string{"cached", "first", "second", "missing", "first"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"cached": "cached value",
+			"first":  "value of first",
+			"second": "value of second",
+		}, values)
+		assert.EqualValues(t, 1, loader.batches.Load())
+
+		_, err = c.LoadMany(context.Background(), []string{"first", "second"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, loader.batches.Load())
+	})
+
+	t.Run("LoaderFunc", func(t *testing.T) {
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), cache.LoaderFunc(
+			func(ctx context.Context, key string) (cache.Loaded, error) {
+				if key == "missing" {
+					return cache.Loaded{}, cache.ErrNotFound
+				}
+				return cache.Loaded{Value: key}, nil
+			},
+		))
+
+		values, err := c.LoadMany(context.Background(), []string{"a", "missing"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "a"}, values)
+	})
+
+	t.Run("Refresh after write", func(t *testing.T) {
+		loader := &batchLoader{}
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), loader, cache.WithRefreshAfter(20*time.Millisecond))
+
+		_, err := c.Load(context.Background(), "key")
+		require.NoError(t, err)
+
+		time.Sleep(30 * time.Millisecond)
+		val, err := c.Load(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, "value of key", val)
+
+		assert.Eventually(t, func() bool {
+			return loader.loads.Load() == 2
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		loader := &batchLoader{delay: 200 * time.Millisecond}
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), loader, cache.WithLoadTimeout(20*time.Millisecond))
+
+		start := time.Now()
+		_, err := c.Load(context.Background(), "key")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("Caller cancellation", func(t *testing.T) {
+		loader := &batchLoader{delay: 50 * time.Millisecond}
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), loader)
+
+		// The first caller giving up does not fail the shared load
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		first := make(chan error, 1)
+		go func() {
+			_, err := c.Load(ctx, "key")
+			first <- err
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		val, err := c.Load(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, "value of key", val)
+		assert.ErrorIs(t, <-first, context.DeadlineExceeded)
+		assert.EqualValues(t, 1, loader.loads.Load())
+	})
+
+	t.Run("Loader panic", func(t *testing.T) {
+		release := make(chan struct{})
+		c := cache.NewLoadingCache(cache.NewMemoryCache(), cache.LoaderFunc(func(ctx context.Context, key string) (cache.Loaded, error) {
+			<-release
+			panic("loader failure")
+		}))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				val, err := c.Load(context.Background(), "key")
+				assert.Nil(t, val)
+				errs <- err
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.ErrorContains(t, err, "loader failure")
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Lock defines the interface for a distributed mutual exclusion lock.
+type Lock interface {
+	// TryLock attempts to acquire the lock once without blocking.
+	// Returns true if the lock is held by this instance, and an error if the operation fails.
+	TryLock() (bool, error)
+
+	// Lock blocks until the lock is acquired or ctx is done,
+	// retrying with exponential backoff.
+	// Returns an error if the operation fails or ctx is done.
+	Lock(ctx context.Context) error
+
+	// Unlock releases the lock only if it is still held by this instance.
+	// Returns true if the lock was released, and an error if the operation fails.
+	Unlock() (bool, error)
+
+	// Extend resets the lock TTL to ttl if it is still held by this instance.
+	// Returns true if the lock was extended, and an error if the operation fails.
+	Extend(ttl time.Duration) (bool, error)
+
+	// Token returns the fencing token of the current acquisition.
+	// Tokens increase monotonically for every acquisition of the same lock name.
+	// Returns zero if the lock is not held, including once its lease may have expired.
+	Token() uint64
+}
+
+// errLockTTL is reported by locks created with a TTL shorter than a millisecond,
+// which Redis cannot store.
+var errLockTTL = errors.New("lock TTL must be at least 1ms")
+
+// lockBackend stores the ownership of a single lock.
+type lockBackend interface {
+	// acquire sets owner as the lock holder if the lock is free.
+	// Returns the new fencing token, or zero if the lock is held by another owner.
+	acquire(owner string, ttl time.Duration) (uint64, error)
+
+	// release removes the lock if it is held by owner.
+	release(owner string) (bool, error)
+
+	// extend resets the lock TTL if it is held by owner.
+	extend(owner string, ttl time.Duration) (bool, error)
+}
+
+// lock is the concrete implementation of the Lock interface over a lockBackend.
+type lock struct {
+	name    string
+	ttl     time.Duration
+	backend lockBackend
+	opt     option
+
+	mutex  sync.Mutex
+	owner  string
+	token  uint64
+	expiry time.Time
+	stop   chan struct{}
+
+	// onChange is called with the mutex held when the lock is acquired or released.
+	onChange func(held bool)
+
+	// onLost is called when a renewal finds the lock taken away.
+	onLost func()
+}
+
+// newLock creates a lock instance over the given backend.
+// A TTL under a millisecond makes every acquisition fail with errLockTTL.
+func newLock(name string, ttl time.Duration, backend lockBackend, opts ...Option) *lock {
+	return &lock{
+		name:    name,
+		ttl:     ttl,
+		backend: backend,
+		opt:     newOption(opts...),
+	}
+}
+
+func (l *lock) TryLock() (_ bool, err error) {
+	defer l.opt.observe("try_lock", l.name, time.Now(), &err)
+
+	if l.ttl < time.Millisecond {
+		return false, &OpError{Op: "try_lock", Key: l.name, Err: errLockTTL}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Confirm a previous acquisition is still valid
+	start := time.Now()
+	if l.owner != "" {
+		ok, err := l.backend.extend(l.owner, l.ttl)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			l.expiry = start.Add(l.ttl)
+			return true, nil
+		}
+		l.reset()
+	}
+
+	owner, err := randomToken()
+	if err != nil {
+		return false, err
+	}
+
+	token, err := l.backend.acquire(owner, l.ttl)
+	if err != nil || token == 0 {
+		return false, err
+	}
+
+	l.owner = owner
+	l.token = token
+	l.expiry = start.Add(l.ttl)
+	if l.onChange != nil {
+		l.onChange(true)
+	}
+	if l.opt.autoRenew {
+		l.stop = make(chan struct{})
+		go l.renew(owner, l.stop)
+	}
+
+	return true, nil
+}
+
+func (l *lock) Lock(ctx context.Context) error {
+	var delay time.Duration
+	for {
+		ok, err := l.TryLock()
+		if err != nil || ok {
+			return err
+		}
+
+		delay = l.opt.backoff(delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *lock) Unlock() (_ bool, err error) {
+	defer l.opt.observe("unlock", l.name, time.Now(), &err)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.owner == "" {
+		return false, nil
+	}
+
+	owner := l.owner
+	l.reset()
+	return l.backend.release(owner)
+}
+
+func (l *lock) Extend(ttl time.Duration) (_ bool, err error) {
+	defer l.opt.observe("extend", l.name, time.Now(), &err)
+
+	if ttl < time.Millisecond {
+		return false, &OpError{Op: "extend", Key: l.name, Err: errLockTTL}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.owner == "" {
+		return false, nil
+	}
+
+	start := time.Now()
+	ok, err := l.backend.extend(l.owner, ttl)
+	if err == nil && !ok {
+		l.reset()
+	} else if ok {
+		l.expiry = start.Add(ttl)
+	}
+
+	return ok, err
+}
+
+func (l *lock) Token() uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// The backend may have expired the lease without this instance being told
+	if l.owner != "" && !time.Now().Before(l.expiry) {
+		l.reset()
+	}
+
+	return l.token
+}
+
+// renew extends the lock periodically until stop is closed or the lock is lost.
+// The lock is considered lost when the backend rejects the renewal or when
+// renewals keep failing until about one renewal interval of the lease is left,
+// so the holder steps down before another process may acquire the lock.
+func (l *lock) renew(owner string, stop <-chan struct{}) {
+	interval := max(l.ttl/3, time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		ok, err := l.backend.extend(owner, l.ttl)
+
+		l.mutex.Lock()
+		if ok && l.owner == owner {
+			l.expiry = start.Add(l.ttl)
+		}
+		expiry := l.expiry
+		l.mutex.Unlock()
+
+		if err != nil {
+			l.opt.logger.Error(
+				"lock renewal failed",
+				slog.String("lock", l.name),
+				slog.Any("error", err),
+			)
+			// Ticks are one interval apart, so half an interval absorbs their jitter
+			if time.Until(expiry) > interval+interval/2 {
+				continue
+			}
+		} else if ok {
+			continue
+		}
+
+		l.opt.logger.Warn("lock lost", slog.String("lock", l.name))
+
+		l.mutex.Lock()
+		lost := l.owner == owner
+		if lost {
+			l.reset()
+		}
+		onLost := l.onLost
+		l.mutex.Unlock()
+
+		if lost && onLost != nil {
+			onLost()
+		}
+		return
+	}
+}
+
+// reset clears the local ownership state and stops renewal.
+// It must be called with the mutex held.
+func (l *lock) reset() {
+	if l.owner != "" && l.onChange != nil {
+		l.onChange(false)
+	}
+
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+
+	l.owner = ""
+	l.token = 0
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// memLocks holds the state of in-memory locks shared within the process.
+var memLocks = struct {
+	sync.Mutex
+	entries map[string]*memLockEntry
+}{
+	entries: make(map[string]*memLockEntry),
+}
+
+// memLockEntry represents the state of a single in-memory lock.
+type memLockEntry struct {
+	owner  string
+	expiry time.Time
+	fence  uint64
+}
+
+// memLockBackend is an in-memory implementation of the lockBackend interface.
+type memLockBackend struct {
+	name string
+}
+
+// NewMemoryLock creates a new in-memory lock instance.
+// Locks with the same name share their state within the process.
+// The TTL must be at least one millisecond.
+func NewMemoryLock(name string, ttl time.Duration, opts ...Option) Lock {
+	return newLock(name, ttl, &memLockBackend{name: name}, opts...)
+}
+
+func (m *memLockBackend) acquire(owner string, ttl time.Duration) (uint64, error) {
+	memLocks.Lock()
+	defer memLocks.Unlock()
+
+	entry, ok := memLocks.entries[m.name]
+	if !ok {
+		entry = &memLockEntry{}
+		memLocks.entries[m.name] = entry
+	}
+
+	now := time.Now()
+	if entry.owner != "" && now.Before(entry.expiry) {
+		return 0, nil
+	}
+
+	entry.fence++
+	entry.owner = owner
+	entry.expiry = now.Add(ttl)
+	return entry.fence, nil
+}
+
+func (m *memLockBackend) release(owner string) (bool, error) {
+	memLocks.Lock()
+	defer memLocks.Unlock()
+
+	entry := m.held(owner)
+	if entry == nil {
+		return false, nil
+	}
+
+	entry.owner = ""
+	return true, nil
+}
+
+func (m *memLockBackend) extend(owner string, ttl time.Duration) (bool, error) {
+	memLocks.Lock()
+	defer memLocks.Unlock()
+
+	entry := m.held(owner)
+	if entry == nil {
+		return false, nil
+	}
+
+	entry.expiry = time.Now().Add(ttl)
+	return true, nil
+}
+
+// held returns the lock entry if it is held by owner and not expired.
+// It must be called with memLocks held.
+func (m *memLockBackend) held(owner string) *memLockEntry {
+	entry, ok := memLocks.entries[m.name]
+	if !ok || entry.owner != owner || !time.Now().Before(entry.expiry) {
+		return nil
+	}
+
+	return entry
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// lockAcquireScript sets the lock if free and returns a new fencing token.
+	lockAcquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+	// lockReleaseScript deletes the lock only if it is held by the owner.
+	lockReleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+	// lockExtendScript resets the lock TTL only if it is held by the owner.
+	lockExtendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// redisLockBackend is a Redis-based implementation of the lockBackend interface.
+type redisLockBackend struct {
+	key    string
+	fence  string
+	client redis.UniversalClient
+}
+
+// NewRedisLock creates a new Redis lock instance with a given name and Redis client.
+// The TTL must be at least one millisecond.
+func NewRedisLock(name string, ttl time.Duration, client redis.UniversalClient, opts ...Option) Lock {
+	return newLock(name, ttl, newRedisLockBackend(name, client, newOption(opts...).keyEncoder), opts...)
+}
+
+// newRedisLockBackend creates the Redis backend of the named lock.
+func newRedisLockBackend(name string, client redis.UniversalClient, encoder KeyEncoder) *redisLockBackend {
+	key := hashTag(encoder.Encode("lock", name))
+	return &redisLockBackend{
+		key:    key,
+		fence:  key + ":fence",
+		client: client,
+	}
+}
+
+func (r *redisLockBackend) acquire(owner string, ttl time.Duration) (uint64, error) {
+	token, err := lockAcquireScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.key, r.fence},
+		owner,
+		ttl.Milliseconds(),
+	).Uint64()
+	return token, redisError("lock", r.key, err)
+}
+
+func (r *redisLockBackend) release(owner string) (bool, error) {
+	released, err := lockReleaseScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.key},
+		owner,
+	).Bool()
+	return released, redisError("unlock", r.key, err)
+}
+
+func (r *redisLockBackend) extend(owner string, ttl time.Duration) (bool, error) {
+	extended, err := lockExtendScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.key},
+		owner,
+		ttl.Milliseconds(),
+	).Bool()
+	return extended, redisError("extend", r.key, err)
+}
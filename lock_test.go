@@ -0,0 +1,199 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{"localhost:6379"}})
+	factories := map[string]func(name string, ttl time.Duration, opts ...cache.Option) cache.Lock{
+		"memory": cache.NewMemoryLock,
+		"redis": func(name string, ttl time.Duration, opts ...cache.Option) cache.Lock {
+			return cache.NewRedisLock(name, ttl, client, opts...)
+		},
+	}
+
+	for name, newLock := range factories {
+		t.Run(name, func(t *testing.T) {
+			t.Run("TryLock and Unlock", func(t *testing.T) {
+				first := newLock("test-lock", time.Minute)
+				second := newLock("test-lock", time.Minute)
+
+				ok, err := first.TryLock()
+				require.NoError(t, err)
+				assert.True(t, ok)
+				token := first.Token()
+				assert.NotZero(t, token)
+
+				ok, err = second.TryLock()
+				require.NoError(t, err)
+				assert.False(t, ok)
+				assert.Zero(t, second.Token())
+
+				released, err := second.Unlock()
+				require.NoError(t, err)
+				assert.False(t, released)
+
+				released, err = first.Unlock()
+				require.NoError(t, err)
+				assert.True(t, released)
+				assert.Zero(t, first.Token())
+
+				ok, err = second.TryLock()
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.Greater(t, second.Token(), token)
+
+				_, err = second.Unlock()
+				require.NoError(t, err)
+			})
+
+			t.Run("Lock with context", func(t *testing.T) {
+				first := newLock("test-lock-ctx", time.Minute)
+				second := newLock("test-lock-ctx", time.Minute, cache.WithRetry(5*time.Millisecond, 10*time.Millisecond))
+
+				err := first.Lock(context.Background())
+				require.NoError(t, err)
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				err = second.Lock(ctx)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+				_, err = first.Unlock()
+				require.NoError(t, err)
+
+				err = second.Lock(context.Background())
+				require.NoError(t, err)
+
+				_, err = second.Unlock()
+				require.NoError(t, err)
+			})
+
+			t.Run("Extend", func(t *testing.T) {
+				first := newLock("test-lock-extend", 100*time.Millisecond)
+				second := newLock("test-lock-extend", time.Minute)
+
+				ok, err := first.TryLock()
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				extended, err := first.Extend(time.Minute)
+				require.NoError(t, err)
+				assert.True(t, extended)
+
+				extended, err = second.Extend(time.Minute)
+				require.NoError(t, err)
+				assert.False(t, extended)
+
+				_, err = first.Unlock()
+				require.NoError(t, err)
+			})
+
+			t.Run("Auto renew", func(t *testing.T) {
+				first := newLock("test-lock-renew", 60*time.Millisecond, cache.WithAutoRenew())
+				second := newLock("test-lock-renew", time.Minute)
+
+				ok, err := first.TryLock()
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				time.Sleep(200 * time.Millisecond)
+				ok, err = second.TryLock()
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				_, err = first.Unlock()
+				require.NoError(t, err)
+			})
+
+			t.Run("Lease expiry", func(t *testing.T) {
+				l := newLock(uniquePrefix("test-lock-lease"), 20*time.Millisecond)
+
+				ok, err := l.TryLock()
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.NotZero(t, l.Token())
+
+				time.Sleep(30 * time.Millisecond)
+				assert.Zero(t, l.Token())
+			})
+
+			t.Run("Invalid TTL", func(t *testing.T) {
+				l := newLock("test-lock-ttl", time.Microsecond)
+				_, err := l.TryLock()
+				assert.Error(t, err)
+
+				l = newLock("test-lock-ttl", time.Minute)
+				ok, err := l.TryLock()
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				_, err = l.Extend(time.Microsecond)
+				assert.Error(t, err)
+
+				_, err = l.Unlock()
+				require.NoError(t, err)
+			})
+		})
+	}
+
+	t.Run("Renewal failures", func(t *testing.T) {
+		limiter := &failingLimiter{}
+		failing := redis.NewClient(&redis.Options{Limiter: limiter})
+		l := cache.NewRedisLock(uniquePrefix("test-lock-failing"), 300*time.Millisecond, failing, cache.WithAutoRenew())
+
+		ok, err := l.TryLock()
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// Wait for a successful renewal before the backend becomes unreachable
+		calls := limiter.calls.Load()
+		require.Eventually(t, func() bool { return limiter.calls.Load() > calls }, time.Second, time.Millisecond)
+		limiter.failing.Store(true)
+		failed := time.Now()
+
+		// The holder steps down one renewal interval before the lease expires
+		require.Eventually(t, func() bool { return l.Token() == 0 }, time.Second, time.Millisecond)
+		assert.Less(t, time.Since(failed), 250*time.Millisecond)
+	})
+
+	t.Run("Hash tag", func(t *testing.T) {
+		l := cache.NewRedisLock("test-lock-slot", time.Minute, client)
+		ok, err := l.TryLock()
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		n, err := client.Exists(context.Background(), "{lock:test-lock-slot}", "{lock:test-lock-slot}:fence").Result()
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, n)
+
+		_, err = l.Unlock()
+		require.NoError(t, err)
+	})
+}
+
+// failingLimiter makes a Redis client fail every command while failing is set.
+type failingLimiter struct {
+	calls   atomic.Int64
+	failing atomic.Bool
+}
+
+func (l *failingLimiter) Allow() error {
+	l.calls.Add(1)
+	if l.failing.Load() {
+		return errors.New("backend unreachable")
+	}
+	return nil
+}
+
+func (l *failingLimiter) ReportResult(error) {}
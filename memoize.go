@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Memoize wraps fn so that its results are cached under keys derived from name
+// and the argument. Arguments are hashed by value, including unexported struct fields
+// and the values behind pointers, so equal arguments share a key across processes.
+// Channels, functions and unsafe pointers are hashed by address, so they only
+// share a key within a process.
+// Results are encoded with the codec set with WithCodec (JSONCodec by default) and
+// stored for ttl, indefinitely if ttl is nil. Concurrent calls with the same argument
+// share a single call of fn, and errors of fn are returned without being cached.
+// The shared call is not cancelled with the context of a caller, callers whose context
+// is done return its error while the call completes for the others.
+func Memoize[K comparable, V any](cache Cache, name string, ttl *time.Duration, fn func(ctx context.Context, arg K) (V, error), opts ...Option) func(ctx context.Context, arg K) (V, error) {
+	o := newOption(opts...)
+	var flights flightGroup
+
+	return func(ctx context.Context, arg K) (V, error) {
+		var zero V
+		key := memoizeKey(name, arg)
+
+		data, exists, err := Lookup(cache, key)
+		if err != nil {
+			return zero, err
+		}
+
+		if exists {
+			var val V
+			err := o.codec.Unmarshal([]byte(toText(data)), &val)
+			if err == nil {
+				return val, nil
+			}
+
+			o.logger.Warn(
+				"memoized value cannot be decoded",
+				slog.String("key", key),
+				slog.Any("error", err),
+			)
+		}
+
+		val, err := flights.do(ctx, key, func(ctx context.Context) (any, error) {
+			val, err := fn(ctx, arg)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := o.codec.Marshal(val)
+			if err != nil {
+				return nil, &OpError{Op: "memoize", Key: key, Err: err}
+			}
+
+			if err := cache.Put(key, string(data), ttl); err != nil {
+				return nil, err
+			}
+
+			return val, nil
+		})
+		if err != nil {
+			return zero, err
+		}
+
+		// A nil result of an interface type V is stored as an untyped nil
+		result, _ := val.(V)
+		return result, nil
+	}
+}
+
+// memoizeKey derives the cache key of a memoized call.
+func memoizeKey(name string, arg any) string {
+	h := sha256.New()
+	hashValue(h, reflect.ValueOf(arg), nil)
+	return name + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// hashRef identifies a pointer, map or slice being hashed.
+type hashRef struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// hashValue writes a stable representation of v to h.
+// Maps are hashed in the order of their hashed keys so that the result is deterministic.
+// Path lists the references being hashed, a reference reached again through a
+// cycle is written as its position in path instead of being followed.
+func hashValue(h hash.Hash, v reflect.Value, path []hashRef) {
+	if !v.IsValid() {
+		h.Write([]byte{0})
+		return
+	}
+
+	h.Write([]byte(v.Type().String()))
+	writeUint := func(n uint64) {
+		h.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+
+		ref := hashRef{ptr: v.Pointer(), typ: v.Type()}
+		if i := slices.Index(path, ref); i >= 0 {
+			writeUint(math.MaxUint64)
+			writeUint(uint64(i))
+			return
+		}
+		path = append(path, ref)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		h.Write([]byte(v.String()))
+	case reflect.Array, reflect.Slice:
+		writeUint(uint64(v.Len()))
+		for i := range v.Len() {
+			hashValue(h, v.Index(i), path)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			h.Write([]byte(v.Type().Field(i).Name))
+			hashValue(h, v.Field(i), path)
+		}
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			writeUint(0)
+		} else {
+			writeUint(1)
+			hashValue(h, v.Elem(), path)
+		}
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Map:
+		entries := make([][]byte, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := sha256.New()
+			hashValue(entry, iter.Key(), path)
+			hashValue(entry, iter.Value(), path)
+			entries = append(entries, entry.Sum(nil))
+		}
+		slices.SortFunc(entries, func(a, b []byte) int { return slices.Compare(a, b) })
+
+		writeUint(uint64(len(entries)))
+		for _, entry := range entries {
+			h.Write(entry)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoQuery is a struct argument of a memoized function.
+type memoQuery struct {
+	Name  string
+	Tags  [2]string
+	page  int
+	Limit *int
+}
+
+// memoResult is a struct result of a memoized function.
+type memoResult struct {
+	Total int
+	Items []string
+}
+
+func TestMemoize(t *testing.T) {
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache("memoize", redis.NewClient(&redis.Options{})),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Forget("search"))
+
+			var calls atomic.Int32
+			ttl := time.Minute
+			search := cache.Memoize(c, "search-"+time.Now().Format(time.RFC3339Nano), &ttl,
+				func(ctx context.Context, q memoQuery) (memoResult, error) {
+					calls.Add(1)
+					time.Sleep(10 * time.Millisecond)
+					return memoResult{Total: q.page, Items: []string{q.Name}}, nil
+				},
+			)
+
+			limit := 10
+			query := memoQuery{Name: "go", Tags: [2]string{"a", "b"}, page: 2, Limit: &limit}
+
+			var wg sync.WaitGroup
+			for range 5 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					res, err := search(context.Background(), query)
+					assert.NoError(t, err)
+					assert.Equal(t, memoResult{Total: 2, Items: []string{"go"}}, res)
+				}()
+			}
+			wg.Wait()
+			assert.EqualValues(t, 1, calls.Load())
+
+			sameLimit := 10
+			res, err := search(context.Background(), memoQuery{Name: "go", Tags: [2]string{"a", "b"}, page: 2, Limit: &sameLimit})
+			require.NoError(t, err)
+			assert.Equal(t, 2, res.Total)
+			assert.EqualValues(t, 1, calls.Load())
+
+			_, err = search(context.Background(), memoQuery{Name: "go", Tags: [2]string{"a", "b"}, page: 3, Limit: &limit})
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, calls.Load())
+		})
+	}
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		calls := 0
+		failure := errors.New("failure")
+		fn := cache.Memoize(cache.NewMemoryCache(), "failing", nil, func(ctx context.Context, id int) (string, error) {
+			calls++
+			return "", failure
+		})
+
+		for range 2 {
+			_, err := fn(context.Background(), 1)
+			assert.ErrorIs(t, err, failure)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Gob codec", func(t *testing.T) {
+		gob.Register(map[string]int{})
+		fn := cache.Memoize(cache.NewMemoryCache(), "gob", nil, func(ctx context.Context, id int) (map[string]int, error) {
+			return map[string]int{"id": id}, nil
+		}, cache.WithCodec(cache.GobCodec))
+
+		for range 2 {
+			res, err := fn(context.Background(), 7)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"id": 7}, res)
+		}
+	})
+
+	t.Run("Nil interface results", func(t *testing.T) {
+		fn := cache.Memoize(cache.NewMemoryCache(), "nil", nil, func(ctx context.Context, id int) (fmt.Stringer, error) {
+			return nil, nil
+		})
+
+		for range 2 {
+			res, err := fn(context.Background(), 1)
+			require.NoError(t, err)
+			assert.Nil(t, res)
+		}
+	})
+
+	t.Run("Channel arguments", func(t *testing.T) {
+		calls := 0
+		fn := cache.Memoize(cache.NewMemoryCache(), "chan", nil, func(ctx context.Context, ch chan int) (int, error) {
+			calls++
+			return calls, nil
+		})
+
+		first, second := make(chan int), make(chan int)
+		for _, ch := range []chan int{first, second, first} {
+			_, err := fn(context.Background(), ch)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Cyclic arguments", func(t *testing.T) {
+		calls := 0
+		fn := cache.Memoize(cache.NewMemoryCache(), "cycle", nil, func(ctx context.Context, n *memoNode) (string, error) {
+			calls++
+			return n.Name, nil
+		})
+
+		a, b := &memoNode{Name: "a"}, &memoNode{Name: "b"}
+		a.Next, b.Next = b, a
+		self := &memoNode{Name: "a"}
+		self.Next = self
+
+		for _, n := range []*memoNode{a, self, a} {
+			res, err := fn(context.Background(), n)
+			require.NoError(t, err)
+			assert.Equal(t, "a", res)
+		}
+		assert.Equal(t, 2, calls)
+	})
+}
+
+// memoNode is a linked list node argument that may form a cycle.
+type memoNode struct {
+	Name string
+	Next *memoNode
+}
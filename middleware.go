@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+// Operation identifies a Cache method passed to middleware hooks.
+type Operation string
+
+const (
+	OpPut            Operation = "put"
+	OpUpdate         Operation = "update"
+	OpPutOrUpdate    Operation = "put_or_update"
+	OpGet            Operation = "get"
+	OpLookup         Operation = "lookup"
+	OpPull           Operation = "pull"
+	OpCast           Operation = "cast"
+	OpExists         Operation = "exists"
+	OpForget         Operation = "forget"
+	OpTTL            Operation = "ttl"
+	OpIncrement      Operation = "increment"
+	OpDecrement      Operation = "decrement"
+	OpIncrementFloat Operation = "increment_float"
+	OpDecrementFloat Operation = "decrement_float"
+)
+
+// Middleware wraps a Cache with additional behaviour.
+type Middleware func(next Cache) Cache
+
+// Chain wraps cache with the given middlewares.
+// The first middleware is the outermost one and runs first.
+func Chain(cache Cache, middlewares ...Middleware) Cache {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			cache = middlewares[i](cache)
+		}
+	}
+	return cache
+}
+
+// Hooks defines callbacks invoked around every cache operation.
+type Hooks struct {
+	// Before runs before the operation is passed to the next cache.
+	// It may rewrite the key or abort the operation by returning an error.
+	Before func(op Operation, key string) (string, error)
+
+	// After runs once the operation completes or is aborted,
+	// with the key passed to the next cache and the resulting error.
+	After func(op Operation, key string, err error)
+}
+
+// NewHookMiddleware creates a middleware that calls hooks around each operation.
+func NewHookMiddleware(hooks Hooks) Middleware {
+	return func(next Cache) Cache {
+		return &hookCache{
+			next:  next,
+			hooks: hooks,
+		}
+	}
+}
+
+// hookCache is a Cache decorator that runs hooks around every operation.
+type hookCache struct {
+	next  Cache
+	hooks Hooks
+}
+
+func (h *hookCache) Put(key string, value any, ttl *time.Duration) error {
+	key, err := h.before(OpPut, key)
+	if err == nil {
+		err = h.next.Put(key, value, ttl)
+	}
+
+	h.after(OpPut, key, err)
+	return err
+}
+
+func (h *hookCache) Update(key string, value any) (bool, error) {
+	var exists bool
+	key, err := h.before(OpUpdate, key)
+	if err == nil {
+		exists, err = h.next.Update(key, value)
+	}
+
+	h.after(OpUpdate, key, err)
+	return exists, err
+}
+
+func (h *hookCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	key, err := h.before(OpPutOrUpdate, key)
+	if err == nil {
+		err = h.next.PutOrUpdate(key, value, ttl)
+	}
+
+	h.after(OpPutOrUpdate, key, err)
+	return err
+}
+
+func (h *hookCache) Get(key string) (any, error) {
+	var val any
+	key, err := h.before(OpGet, key)
+	if err == nil {
+		val, err = h.next.Get(key)
+	}
+
+	h.after(OpGet, key, err)
+	return val, err
+}
+
+func (h *hookCache) Lookup(key string) (any, bool, error) {
+	var val any
+	var exists bool
+	key, err := h.before(OpLookup, key)
+	if err == nil {
+		val, exists, err = Lookup(h.next, key)
+	}
+
+	h.after(OpLookup, key, err)
+	return val, exists, err
+}
+
+func (h *hookCache) Pull(key string) (any, error) {
+	var val any
+	key, err := h.before(OpPull, key)
+	if err == nil {
+		val, err = h.next.Pull(key)
+	}
+
+	h.after(OpPull, key, err)
+	return val, err
+}
+
+func (h *hookCache) Cast(key string) (cast.Caster, error) {
+	key, err := h.before(OpCast, key)
+	if err != nil {
+		h.after(OpCast, key, err)
+		return cast.NewCaster(nil), err
+	}
+
+	caster, err := h.next.Cast(key)
+	h.after(OpCast, key, err)
+	return caster, err
+}
+
+func (h *hookCache) Exists(key string) (bool, error) {
+	var exists bool
+	key, err := h.before(OpExists, key)
+	if err == nil {
+		exists, err = h.next.Exists(key)
+	}
+
+	h.after(OpExists, key, err)
+	return exists, err
+}
+
+func (h *hookCache) Forget(key string) error {
+	key, err := h.before(OpForget, key)
+	if err == nil {
+		err = h.next.Forget(key)
+	}
+
+	h.after(OpForget, key, err)
+	return err
+}
+
+func (h *hookCache) TTL(key string) (time.Duration, error) {
+	var ttl time.Duration
+	key, err := h.before(OpTTL, key)
+	if err == nil {
+		ttl, err = h.next.TTL(key)
+	}
+
+	h.after(OpTTL, key, err)
+	return ttl, err
+}
+
+func (h *hookCache) Increment(key string, value int64) (bool, error) {
+	var exists bool
+	key, err := h.before(OpIncrement, key)
+	if err == nil {
+		exists, err = h.next.Increment(key, value)
+	}
+
+	h.after(OpIncrement, key, err)
+	return exists, err
+}
+
+func (h *hookCache) Decrement(key string, value int64) (bool, error) {
+	var exists bool
+	key, err := h.before(OpDecrement, key)
+	if err == nil {
+		exists, err = h.next.Decrement(key, value)
+	}
+
+	h.after(OpDecrement, key, err)
+	return exists, err
+}
+
+func (h *hookCache) IncrementFloat(key string, value float64) (bool, error) {
+	var exists bool
+	key, err := h.before(OpIncrementFloat, key)
+	if err == nil {
+		exists, err = h.next.IncrementFloat(key, value)
+	}
+
+	h.after(OpIncrementFloat, key, err)
+	return exists, err
+}
+
+func (h *hookCache) DecrementFloat(key string, value float64) (bool, error) {
+	var exists bool
+	key, err := h.before(OpDecrementFloat, key)
+	if err == nil {
+		exists, err = h.next.DecrementFloat(key, value)
+	}
+
+	h.after(OpDecrementFloat, key, err)
+	return exists, err
+}
+
+// before runs the before hook if defined.
+// The original key is kept when the hook aborts the operation.
+func (h *hookCache) before(op Operation, key string) (string, error) {
+	if h.hooks.Before == nil {
+		return key, nil
+	}
+
+	rewritten, err := h.hooks.Before(op, key)
+	if err != nil {
+		return key, err
+	}
+
+	return rewritten, nil
+}
+
+// after runs the after hook if defined.
+func (h *hookCache) after(op Operation, key string, err error) {
+	if h.hooks.After != nil {
+		h.hooks.After(op, key, err)
+	}
+}
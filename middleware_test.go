@@ -0,0 +1,87 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Chain order", func(t *testing.T) {
+		var calls []string
+		tracer := func(name string) cache.Middleware {
+			return cache.NewHookMiddleware(cache.Hooks{
+				Before: func(op cache.Operation, key string) (string, error) {
+					calls = append(calls, name+" before "+string(op))
+					return key, nil
+				},
+				After: func(op cache.Operation, key string, err error) {
+					calls = append(calls, name+" after "+string(op))
+				},
+			})
+		}
+
+		c := cache.Chain(cache.NewMemoryCache(), tracer("outer"), tracer("inner"))
+		err := c.Put("key", "value", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"outer before put",
+			"inner before put",
+			"inner after put",
+			"outer after put",
+		}, calls)
+	})
+
+	t.Run("Key rewrite", func(t *testing.T) {
+		base := cache.NewMemoryCache()
+		c := cache.Chain(base, cache.NewHookMiddleware(cache.Hooks{
+			Before: func(op cache.Operation, key string) (string, error) {
+				return "tenant-" + key, nil
+			},
+		}))
+
+		err := c.Put("key", "value", nil)
+		require.NoError(t, err)
+
+		value, err := base.Get("tenant-key")
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		value, err = c.Get("key")
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		denied := errors.New("denied")
+		var afterErr error
+
+		base := cache.NewMemoryCache()
+		c := cache.Chain(base, cache.NewHookMiddleware(cache.Hooks{
+			Before: func(op cache.Operation, key string) (string, error) {
+				if op == cache.OpForget {
+					return "", denied
+				}
+				return key, nil
+			},
+			After: func(op cache.Operation, key string, err error) {
+				afterErr = err
+			},
+		}))
+
+		err := c.Put("key", "value", nil)
+		require.NoError(t, err)
+
+		err = c.Forget("key")
+		assert.ErrorIs(t, err, denied)
+		assert.ErrorIs(t, afterErr, denied)
+
+		exists, err := base.Exists("key")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-universal/cast"
+)
+
+// migratingCache is a Cache moving entries from an old backend to a new one.
+type migratingCache struct {
+	from Cache
+	to   Cache
+}
+
+// NewMigratingCache creates a cache for online migration from one backend to another.
+// Writes are applied to both backends, the old one first. Reads use the new backend
+// and fall back to the old one, copying the entries found there with their remaining TTL.
+// Once every entry has expired or been copied, for example with Export and Import,
+// the old backend can be dropped. Hashes, sets and sorted sets are not migrated.
+func NewMigratingCache(from, to Cache) Cache {
+	return &migratingCache{
+		from: from,
+		to:   to,
+	}
+}
+
+func (m *migratingCache) Put(key string, value any, ttl *time.Duration) error {
+	if err := m.from.Put(key, value, ttl); err != nil {
+		return err
+	}
+
+	return m.to.Put(key, value, ttl)
+}
+
+func (m *migratingCache) Update(key string, value any) (bool, error) {
+	return m.dual(key, func(c Cache) (bool, error) { return c.Update(key, value) })
+}
+
+func (m *migratingCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	ok, err := m.Update(key, value)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return m.Put(key, value, ttl)
+	}
+
+	return nil
+}
+
+func (m *migratingCache) Get(key string) (any, error) {
+	val, _, err := m.Lookup(key)
+	return val, err
+}
+
+// Lookup copies entries only found in the old backend to the new one.
+func (m *migratingCache) Lookup(key string) (any, bool, error) {
+	val, exists, err := Lookup(m.to, key)
+	if err != nil || exists {
+		return val, exists, err
+	}
+
+	val, exists, err = Lookup(m.from, key)
+	if err != nil || !exists {
+		return val, exists, err
+	}
+
+	if err := m.copy(key, val); err != nil {
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+func (m *migratingCache) Pull(key string) (any, error) {
+	val, err := m.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Forget(key); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (m *migratingCache) Cast(key string) (cast.Caster, error) {
+	val, err := m.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cast.NewCaster(val), nil
+}
+
+func (m *migratingCache) Exists(key string) (bool, error) {
+	exists, err := m.to.Exists(key)
+	if err != nil || exists {
+		return exists, err
+	}
+
+	return m.from.Exists(key)
+}
+
+func (m *migratingCache) Forget(key string) error {
+	return errors.Join(m.from.Forget(key), m.to.Forget(key))
+}
+
+func (m *migratingCache) TTL(key string) (time.Duration, error) {
+	exists, err := m.to.Exists(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if exists {
+		return m.to.TTL(key)
+	}
+
+	return m.from.TTL(key)
+}
+
+func (m *migratingCache) Increment(key string, value int64) (bool, error) {
+	return m.dual(key, func(c Cache) (bool, error) { return c.Increment(key, value) })
+}
+
+func (m *migratingCache) Decrement(key string, value int64) (bool, error) {
+	return m.dual(key, func(c Cache) (bool, error) { return c.Decrement(key, value) })
+}
+
+func (m *migratingCache) IncrementFloat(key string, value float64) (bool, error) {
+	return m.dual(key, func(c Cache) (bool, error) { return c.IncrementFloat(key, value) })
+}
+
+func (m *migratingCache) DecrementFloat(key string, value float64) (bool, error) {
+	return m.dual(key, func(c Cache) (bool, error) { return c.DecrementFloat(key, value) })
+}
+
+// dual applies a modification of an existing key to both backends.
+// If only the old backend holds the key, its resulting value is copied to the new one.
+func (m *migratingCache) dual(key string, modify func(c Cache) (bool, error)) (bool, error) {
+	ok, err := modify(m.from)
+	if err != nil {
+		return false, err
+	}
+
+	migrated, err := modify(m.to)
+	if err != nil || migrated || !ok {
+		return ok || migrated, err
+	}
+
+	val, exists, err := Lookup(m.from, key)
+	if err != nil || !exists {
+		return ok, err
+	}
+
+	return true, m.copy(key, val)
+}
+
+// copy stores the value of key in the new backend with the remaining TTL of the old one.
+func (m *migratingCache) copy(key string, val any) error {
+	ttl, exists, err := remainingTTL(m.from, key)
+	if err != nil || !exists {
+		return err
+	}
+
+	return m.to.Put(key, val, ttl)
+}
@@ -0,0 +1,89 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratingCache(t *testing.T) {
+	clock := cache.NewFakeClock(time.Now())
+	cachetest.RunCacheSuite(t, func(t *testing.T) cache.Cache {
+		return cache.NewMigratingCache(cache.NewMemoryCache(cache.WithClock(clock)), cache.NewMemoryCache(cache.WithClock(clock)))
+	}, cachetest.WithAdvance(clock.Advance))
+
+	testTypedValues(t, cache.NewMigratingCache(cache.NewMemoryCache(), cache.NewMemoryCache()))
+
+	t.Run("Dual write", func(t *testing.T) {
+		from, to := cache.NewMemoryCache(), cache.NewMemoryCache()
+		c := cache.NewMigratingCache(from, to)
+		require.NoError(t, c.Put("key", "value", nil))
+
+		for _, backend := range []cache.Cache{from, to} {
+			val, err := backend.Get("key")
+			require.NoError(t, err)
+			assert.Equal(t, "value", val)
+		}
+
+		require.NoError(t, c.Forget("key"))
+		exists, err := from.Exists("key")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Read fallback", func(t *testing.T) {
+		from, to := cache.NewMemoryCache(), cache.NewMemoryCache()
+		ttl := time.Hour
+		require.NoError(t, from.Put("old", "value", &ttl))
+		require.NoError(t, from.Put("counter", 1, nil))
+
+		c := cache.NewMigratingCache(from, to)
+		val, err := c.Get("old")
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+
+		val, err = to.Get("old")
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+
+		remaining, err := to.TTL("old")
+		require.NoError(t, err)
+		assert.Greater(t, remaining, 59*time.Minute)
+
+		ok, err := c.Increment("counter", 2)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		val, err = to.Get("counter")
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, val)
+	})
+
+	t.Run("Deleted during copy", func(t *testing.T) {
+		from, to := cache.NewMemoryCache(), cache.NewMemoryCache()
+		require.NoError(t, from.Put("key", "value", nil))
+
+		// The Redis cache reports keys deleted since the read with a TTL of -2
+		c := cache.NewMigratingCache(deletedCache{from}, to)
+		val, err := c.Get("key")
+		require.NoError(t, err)
+		assert.Equal(t, "value", val)
+
+		exists, err := to.Exists("key")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+// deletedCache reports every key as deleted when asked for its TTL.
+type deletedCache struct {
+	cache.Cache
+}
+
+func (deletedCache) TTL(string) (time.Duration, error) {
+	return -2, nil
+}
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option configures optional behaviour of the constructors in this package.
+// The constructors share one set of options and silently ignore the ones that do
+// not apply to them; the documentation of each option lists who honors it.
+type Option func(*option)
+
+// option holds the optional settings shared by the constructors.
+type option struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+	retryMin      time.Duration
+	retryMax      time.Duration
+	autoRenew     bool
+	cleanup       *time.Duration
+	clock         Clock
+	codec         Codec
+	keyEncoder    KeyEncoder
+	refreshAfter  time.Duration
+	loadTimeout   time.Duration
+	writeBehind   bool
+	flushInterval time.Duration
+	flushSize     int
+
+	snapshotPath     string
+	snapshotInterval time.Duration
+}
+
+// newOption creates the default settings and applies the given options.
+func newOption(opts ...Option) option {
+	o := option{
+		logger:        slog.New(slog.DiscardHandler),
+		slowThreshold: 100 * time.Millisecond,
+		retryMin:      50 * time.Millisecond,
+		retryMax:      time.Second,
+		codec:         JSONCodec,
+		keyEncoder:    DefaultKeyEncoder,
+		clock:         SystemClock,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return o
+}
+
+// WithLogger sets the logger used to report errors, slow operations,
+// evictions and rate limiter lockouts. It is honored by every constructor.
+// Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *option) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
+
+// WithSlowThreshold sets the duration after which an operation is logged as slow.
+// It is honored by every constructor. Zero disables slow operation logging.
+// Defaults to 100ms.
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(o *option) {
+		o.slowThreshold = threshold
+	}
+}
+
+// WithRetry sets the backoff used by blocking acquisitions of locks, leader
+// electors and semaphores, and by writing caches retrying failed writes. The delay starts at minDelay and doubles after each failed attempt up to maxDelay.
+// Defaults to 50ms and 1s.
+func WithRetry(minDelay, maxDelay time.Duration) Option {
+	return func(o *option) {
+		if minDelay > 0 {
+			o.retryMin = minDelay
+		}
+		o.retryMax = max(maxDelay, o.retryMin)
+	}
+}
+
+// WithAutoRenew keeps the leases of locks acquired from NewMemoryLock and NewRedisLock
+// alive by extending them periodically until they are released.
+func WithAutoRenew() Option {
+	return func(o *option) {
+		o.autoRenew = true
+	}
+}
+
+// WithCleanupInterval sets the interval of the background removal of expired
+// entries of the memory, disk and SQL caches. The SQL cache sweeps every minute and the disk cache every ten minutes
+// by default. The memory cache removes expired entries when they are read and
+// only sweeps if an interval is set. Zero disables it.
+func WithCleanupInterval(interval time.Duration) Option {
+	return func(o *option) {
+		o.cleanup = &interval
+	}
+}
+
+// WithClock sets the clock used by the memory and disk caches for expiry and
+// background tasks. Defaults to SystemClock; use a FakeClock to test TTLs without waiting.
+func WithClock(clock Clock) Option {
+	return func(o *option) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// WithCodec sets the codec used to encode values by Export, Import, Memoize
+// and the HTTP middleware and transport. Defaults to JSONCodec.
+func WithCodec(codec Codec) Option {
+	return func(o *option) {
+		if codec != nil {
+			o.codec = codec
+		}
+	}
+}
+
+// WithKeyEncoder sets how backends with a restricted key space encode keys.
+// It is honored by the Redis and memcached caches and by the Redis locks, leader
+// electors, semaphores, bloom filters and HyperLogLogs. Defaults to DefaultKeyEncoder.
+func WithKeyEncoder(encoder KeyEncoder) Option {
+	return func(o *option) {
+		if encoder != nil {
+			o.keyEncoder = encoder
+		}
+	}
+}
+
+// WithRefreshAfter makes caches created by NewLoadingCache reload values older than age
+// in the background while still returning the cached value.
+func WithRefreshAfter(age time.Duration) Option {
+	return func(o *option) {
+		o.refreshAfter = age
+	}
+}
+
+// WithLoadTimeout limits the duration of loader calls of caches created by NewLoadingCache.
+// Zero disables the limit.
+func WithLoadTimeout(timeout time.Duration) Option {
+	return func(o *option) {
+		o.loadTimeout = timeout
+	}
+}
+
+// WithWriteBehind makes caches created by NewWritingCache buffer changes and flush them
+// every interval or once size keys are pending. Zero size disables size-triggered flushes.
+func WithWriteBehind(interval time.Duration, size int) Option {
+	return func(o *option) {
+		if interval > 0 {
+			o.writeBehind = true
+			o.flushInterval = interval
+			o.flushSize = size
+		}
+	}
+}
+
+// WithSnapshotFile makes the memory cache load its entries from path on construction
+// and save them to path every interval and when closed. Zero interval only saves on close.
+func WithSnapshotFile(path string, interval time.Duration) Option {
+	return func(o *option) {
+		o.snapshotPath = path
+		o.snapshotInterval = interval
+	}
+}
+
+// backoff returns the delay to wait after the given delay.
+func (o option) backoff(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return o.retryMin
+	}
+
+	return min(delay*2, o.retryMax)
+}
+
+// observe logs a failed or slow operation started at start.
+// It is meant to be deferred with a pointer to the named error result.
+func (o option) observe(op, key string, start time.Time, err *error) {
+	elapsed := time.Since(start)
+	if err != nil && *err != nil {
+		o.logger.Error(
+			"cache operation failed",
+			slog.String("op", op),
+			slog.String("key", key),
+			slog.Duration("elapsed", elapsed),
+			slog.Any("error", *err),
+		)
+		return
+	}
+
+	if o.slowThreshold > 0 && elapsed > o.slowThreshold {
+		o.logger.Warn(
+			"slow cache operation",
+			slog.String("op", op),
+			slog.String("key", key),
+			slog.Duration("elapsed", elapsed),
+			slog.Duration("threshold", o.slowThreshold),
+		)
+	}
+}
@@ -0,0 +1,79 @@
+package cache_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	newLogger := func() (*slog.Logger, *bytes.Buffer) {
+		buf := &bytes.Buffer{}
+		handler := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+		return slog.New(handler), buf
+	}
+
+	t.Run("Eviction", func(t *testing.T) {
+		logger, buf := newLogger()
+		memCache := cache.NewMemoryCache(cache.WithLogger(logger))
+
+		ttl := time.Millisecond
+		err := memCache.Put("key", "value", &ttl)
+		require.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+		value, err := memCache.Get("key")
+		require.NoError(t, err)
+		assert.Nil(t, value)
+		assert.Contains(t, buf.String(), `msg="cache entry evicted" key=key reason=expired`)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		logger, buf := newLogger()
+		memCache := cache.NewMemoryCache(cache.WithLogger(logger))
+
+		err := memCache.Put("key", "value", nil)
+		require.NoError(t, err)
+
+		_, err = memCache.Increment("key", 1)
+		require.Error(t, err)
+		assert.Contains(t, buf.String(), `msg="cache operation failed" op=increment key=key`)
+	})
+
+	t.Run("Lockout", func(t *testing.T) {
+		logger, buf := newLogger()
+		limiter := cache.NewRateLimiter(
+			"login", 2, time.Minute,
+			cache.NewMemoryCache(),
+			cache.WithLogger(logger),
+		)
+
+		err := limiter.Hit()
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "rate limiter locked")
+
+		err = limiter.Hit()
+		require.NoError(t, err)
+		locked, err := limiter.MustLock()
+		require.NoError(t, err)
+		assert.True(t, locked)
+		assert.Contains(t, buf.String(), `msg="rate limiter locked" limiter="limiter login" max_attempts=2`)
+
+		// The lockout is reported once until the limiter is unlocked
+		require.NoError(t, limiter.Hit())
+		require.NoError(t, limiter.Lock())
+		_, err = limiter.MustLock()
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(buf.String(), "rate limiter locked"))
+
+		require.NoError(t, limiter.Reset())
+		require.NoError(t, limiter.Lock())
+		assert.Equal(t, 2, strings.Count(buf.String(), "rate limiter locked"))
+	})
+}
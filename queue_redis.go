@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-universal/cast"
 	"github.com/redis/go-redis/v9"
@@ -11,22 +12,28 @@ import (
 // redisQueue represents a Redis-backed queue.
 type redisQueue struct {
 	name   string
-	client *redis.Client
+	client redis.UniversalClient
+	opt    option
 }
 
 // NewRedisQueue creates a new Redis queue instance.
-func NewRedisQueue(name string, client *redis.Client) Queue {
+func NewRedisQueue(name string, client redis.UniversalClient, opts ...Option) Queue {
 	return &redisQueue{
 		name:   name,
 		client: client,
+		opt:    newOption(opts...),
 	}
 }
 
-func (r *redisQueue) Push(value any) error {
-	return r.client.LPush(context.Background(), r.name, value).Err()
+func (r *redisQueue) Push(value any) (err error) {
+	defer r.finish("push", r.name, time.Now(), &err)
+
+	return r.client.RPush(context.Background(), r.name, value).Err()
 }
 
-func (r *redisQueue) Pull() (any, error) {
+func (r *redisQueue) Pull() (_ any, err error) {
+	defer r.finish("pull", r.name, time.Now(), &err)
+
 	val, err := r.client.LPop(context.Background(), r.name).Result()
 
 	if errors.Is(err, redis.Nil) {
@@ -40,7 +47,9 @@ func (r *redisQueue) Pull() (any, error) {
 	return val, nil
 }
 
-func (r *redisQueue) Pop() (any, error) {
+func (r *redisQueue) Pop() (_ any, err error) {
+	defer r.finish("pop", r.name, time.Now(), &err)
+
 	val, err := r.client.RPop(context.Background(), r.name).Result()
 
 	if errors.Is(err, redis.Nil) {
@@ -59,7 +68,9 @@ func (r *redisQueue) Cast() (cast.Caster, error) {
 	return cast.NewCaster(val), err
 }
 
-func (r *redisQueue) Length() (int64, error) {
+func (r *redisQueue) Length() (_ int64, err error) {
+	defer r.finish("length", r.name, time.Now(), &err)
+
 	val, err := r.client.LLen(context.Background(), r.name).Result()
 
 	if errors.Is(err, redis.Nil) {
@@ -73,6 +84,14 @@ func (r *redisQueue) Length() (int64, error) {
 	return val, nil
 }
 
-func (r *redisQueue) Clear() error {
+func (r *redisQueue) Clear() (err error) {
+	defer r.finish("clear", r.name, time.Now(), &err)
+
 	return r.client.Del(context.Background(), r.name).Err()
 }
+
+// finish maps the error of a completed operation and logs it.
+func (r *redisQueue) finish(op, key string, start time.Time, err *error) {
+	*err = redisError(op, key, *err)
+	r.opt.observe(op, key, start, err)
+}
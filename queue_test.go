@@ -4,13 +4,19 @@ import (
 	"testing"
 
 	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestRedisQueue(t *testing.T) {
-	queue := cache.NewRedisQueue("test-queue", redis.NewClient(&redis.Options{}))
+	client := redis.NewClient(&redis.Options{})
+	cachetest.RunQueueSuite(t, func(t *testing.T) cache.Queue {
+		return cache.NewRedisQueue(uniquePrefix("queue"), client)
+	})
+
+	queue := cache.NewRedisQueue("test-queue", client)
 
 	t.Run("Push and Length", func(t *testing.T) {
 		err := queue.Clear()
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// negativePrefix marks the keys of not-found entries. Not-found entries live
+// under their own keys so that every cache method sees the original key as missing.
+const negativePrefix = "\x1ego-universal/cache:not-found\x1e"
+
+// errMissTTL is reported by RememberNegative without a lifetime for not-found entries.
+var errMissTTL = errors.New("missTTL is required")
+
+// Remember returns the cached value of key, or calls loader and stores
+// its result with the given ttl. If ttl is nil, the value is stored indefinitely.
+// Loader errors are returned as is and nothing is cached. Storing a value
+// clears the not-found entry cached for key by RememberNegative.
+func Remember(cache Cache, key string, ttl *time.Duration, loader func() (any, error)) (any, error) {
+	val, exists, err := Lookup(cache, key)
+	if err != nil || exists {
+		return val, err
+	}
+
+	val, err = loader()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storeRemembered(cache, key, val, ttl); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// RememberNegative behaves like Remember but also caches misses.
+// When loader returns an error matching ErrNotFound, a not-found entry is stored
+// for missTTL and later calls return ErrNotFound without calling loader until it expires.
+// The not-found entry is kept under a separate key, so key itself stays missing;
+// use ForgetRemembered to clear it once a value exists. A nil missTTL is rejected
+// as not-found entries would never expire.
+func RememberNegative(cache Cache, key string, ttl, missTTL *time.Duration, loader func() (any, error)) (any, error) {
+	if missTTL == nil {
+		return nil, &OpError{Op: "remember", Key: key, Err: errMissTTL}
+	}
+
+	val, exists, err := Lookup(cache, key)
+	if err != nil || exists {
+		return val, err
+	}
+
+	missing, err := cache.Exists(negativeKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if missing {
+		return nil, &OpError{Op: "remember", Key: key, Err: ErrNotFound}
+	}
+
+	val, err = loader()
+	if errors.Is(err, ErrNotFound) {
+		if err := cache.Put(negativeKey(key), true, missTTL); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storeRemembered(cache, key, val, ttl); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// ForgetRemembered removes key and the not-found entry cached for it by
+// RememberNegative, so that the next call loads it again.
+func ForgetRemembered(cache Cache, key string) error {
+	if err := cache.Forget(negativeKey(key)); err != nil {
+		return err
+	}
+
+	return cache.Forget(key)
+}
+
+// storeRemembered caches a loaded value and clears the not-found entry of its key.
+func storeRemembered(cache Cache, key string, val any, ttl *time.Duration) error {
+	if err := cache.Put(key, val, ttl); err != nil {
+		return err
+	}
+
+	return cache.Forget(negativeKey(key))
+}
+
+// negativeKey returns the key of the not-found entry of key.
+func negativeKey(key string) string {
+	return negativePrefix + key
+}
@@ -0,0 +1,107 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemember(t *testing.T) {
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache(uniquePrefix("remember"), redis.NewClient(&redis.Options{})),
+		"chain":  cache.Chain(cache.NewMemoryCache(), cache.NewHookMiddleware(cache.Hooks{})),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			ttl := 10 * time.Second
+			missTTL := time.Second
+
+			t.Run("Remember", func(t *testing.T) {
+				require.NoError(t, c.Forget("rememberKey"))
+
+				calls := 0
+				loader := func() (any, error) {
+					calls++
+					return "value", nil
+				}
+
+				for range 2 {
+					value, err := cache.Remember(c, "rememberKey", &ttl, loader)
+					require.NoError(t, err)
+					assert.Equal(t, "value", value)
+				}
+				assert.Equal(t, 1, calls)
+			})
+
+			t.Run("Loader error", func(t *testing.T) {
+				require.NoError(t, c.Forget("failingKey"))
+
+				failure := errors.New("failure")
+				_, err := cache.RememberNegative(c, "failingKey", &ttl, &missTTL, func() (any, error) {
+					return nil, failure
+				})
+				assert.ErrorIs(t, err, failure)
+
+				exists, err := c.Exists("failingKey")
+				require.NoError(t, err)
+				assert.False(t, exists)
+			})
+
+			t.Run("Negative", func(t *testing.T) {
+				require.NoError(t, c.Forget("missingRow"))
+
+				calls := 0
+				loader := func() (any, error) {
+					calls++
+					return nil, cache.ErrNotFound
+				}
+
+				for range 2 {
+					value, err := cache.RememberNegative(c, "missingRow", &ttl, &missTTL, loader)
+					assert.ErrorIs(t, err, cache.ErrNotFound)
+					assert.Nil(t, value)
+				}
+				assert.Equal(t, 1, calls)
+
+				value, exists, err := cache.Lookup(c, "missingRow")
+				require.NoError(t, err)
+				assert.False(t, exists)
+				assert.Nil(t, value)
+
+				value, err = c.Get("missingRow")
+				require.NoError(t, err)
+				assert.Nil(t, value)
+
+				exists, err = c.Exists("missingRow")
+				require.NoError(t, err)
+				assert.False(t, exists)
+
+				ok, err := c.Update("missingRow", "value")
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				ok, err = c.Increment("missingRow", 1)
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				// A row inserted after the miss is loaded once the miss is forgotten
+				require.NoError(t, cache.ForgetRemembered(c, "missingRow"))
+				value, err = cache.RememberNegative(c, "missingRow", &ttl, &missTTL, func() (any, error) {
+					return "row", nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, "row", value)
+
+				_, err = cache.RememberNegative(c, "missingRow", &ttl, nil, loader)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	// errPermits is returned when a semaphore operation uses an invalid number of permits.
+	errPermits = errors.New("invalid number of permits")
+
+	// errLeaseLost is returned when permits are released after their lease expired.
+	errLeaseLost = errors.New("semaphore lease lost")
+)
+
+// Semaphore defines the interface for a distributed counting semaphore.
+// Permits are granted in request order and held through a lease that
+// expires after the semaphore TTL if the holder process dies.
+type Semaphore interface {
+	// Acquire blocks until n permits are acquired or ctx is done.
+	// Waiters are served in the order they started waiting.
+	// Returns an error if the operation fails or ctx is done.
+	Acquire(ctx context.Context, n int64) error
+
+	// TryAcquire attempts to acquire n permits without blocking.
+	// It fails when other callers are already waiting.
+	// Returns true if the permits were acquired, and an error if the operation fails.
+	TryAcquire(n int64) (bool, error)
+
+	// Release returns n previously acquired permits.
+	// Returns an error if the operation fails, more permits than held are released
+	// or the lease holding them has expired.
+	Release(n int64) error
+}
+
+// semaphoreBackend stores the holders and waiters of a single semaphore.
+type semaphoreBackend interface {
+	// acquire grants n permits to holder if they are available and waiter
+	// is first in line. An empty waiter only succeeds when nobody is waiting.
+	// Holders are kept alive for ttl, waiters are queued on their first attempt
+	// and kept alive for wait.
+	acquire(holder, waiter string, n, limit int64, ttl, wait time.Duration) (bool, error)
+
+	// release returns n permits held by holder.
+	// Returns false if the lease has expired.
+	release(holder string, n int64) (bool, error)
+
+	// renew extends the lease of holder.
+	// Returns false if the lease has expired.
+	renew(holder string, ttl time.Duration) (bool, error)
+
+	// leave removes waiter from the queue.
+	leave(waiter string) error
+}
+
+// semaphore is the concrete implementation of the Semaphore interface over a semaphoreBackend.
+type semaphore struct {
+	name    string
+	limit   int64
+	ttl     time.Duration
+	backend semaphoreBackend
+	opt     option
+
+	mutex  sync.Mutex
+	holder string
+	held   int64
+	stop   chan struct{}
+}
+
+// newSemaphore creates a semaphore instance over the given backend.
+func newSemaphore(name string, limit int64, ttl time.Duration, backend semaphoreBackend, opts ...Option) *semaphore {
+	return &semaphore{
+		name:    name,
+		limit:   limit,
+		ttl:     ttl,
+		backend: backend,
+		opt:     newOption(opts...),
+	}
+}
+
+func (s *semaphore) Acquire(ctx context.Context, n int64) (err error) {
+	defer s.opt.observe("acquire", s.name, time.Now(), &err)
+
+	if n <= 0 || n > s.limit {
+		return errPermits
+	}
+
+	waiter, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	var delay time.Duration
+	for {
+		ok, err := s.acquire(waiter, n)
+		if err != nil {
+			return errors.Join(err, s.backend.leave(waiter))
+		}
+
+		if ok {
+			return nil
+		}
+
+		delay = s.opt.backoff(delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(ctx.Err(), s.backend.leave(waiter))
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *semaphore) TryAcquire(n int64) (_ bool, err error) {
+	defer s.opt.observe("try_acquire", s.name, time.Now(), &err)
+
+	if n <= 0 || n > s.limit {
+		return false, errPermits
+	}
+
+	return s.acquire("", n)
+}
+
+func (s *semaphore) Release(n int64) (err error) {
+	defer s.opt.observe("release", s.name, time.Now(), &err)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if n <= 0 || n > s.held {
+		return errPermits
+	}
+
+	ok, err := s.backend.release(s.holder, n)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		s.held = 0
+		s.reset()
+		return errLeaseLost
+	}
+
+	s.held -= n
+	if s.held == 0 {
+		s.reset()
+	}
+
+	return nil
+}
+
+// acquire performs a single acquisition attempt and starts the lease renewal.
+func (s *semaphore) acquire(waiter string, n int64) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.holder == "" {
+		holder, err := randomToken()
+		if err != nil {
+			return false, err
+		}
+		s.holder = holder
+	}
+
+	// Waiters must outlive the longest retry delay to keep their place in line
+	wait := max(s.ttl, 2*s.opt.retryMax)
+	start := time.Now()
+	ok, err := s.backend.acquire(s.holder, waiter, n, s.limit, s.ttl, wait)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	s.held += n
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		go s.renew(s.holder, s.stop, start.Add(s.ttl))
+	}
+
+	return true, nil
+}
+
+// renew extends the holder lease expiring at expiry periodically until stop is closed
+// or the lease is lost.
+// Like lock renewals, failing renewals give the permits up once about one renewal
+// interval of the lease is left, before other holders may be granted them.
+func (s *semaphore) renew(holder string, stop <-chan struct{}, expiry time.Time) {
+	interval := max(s.ttl/3, time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		ok, err := s.backend.renew(holder, s.ttl)
+		if err != nil {
+			s.opt.logger.Error(
+				"semaphore renewal failed",
+				slog.String("semaphore", s.name),
+				slog.Any("error", err),
+			)
+			if time.Until(expiry) > interval+interval/2 {
+				continue
+			}
+		} else if ok {
+			expiry = start.Add(s.ttl)
+			continue
+		}
+
+		s.opt.logger.Warn("semaphore lease lost", slog.String("semaphore", s.name))
+
+		s.mutex.Lock()
+		if s.holder == holder {
+			s.held = 0
+			s.reset()
+		}
+		s.mutex.Unlock()
+		return
+	}
+}
+
+// reset stops the lease renewal and starts a new holder identity.
+// It must be called with the mutex held.
+func (s *semaphore) reset() {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+
+	s.holder = ""
+}
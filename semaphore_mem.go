@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// memSemaphores holds the state of in-memory semaphores shared within the process.
+var memSemaphores = struct {
+	sync.Mutex
+	entries map[string]*memSemaphoreEntry
+}{
+	entries: make(map[string]*memSemaphoreEntry),
+}
+
+// memSemaphoreEntry represents the holders and waiters of a single in-memory semaphore.
+type memSemaphoreEntry struct {
+	holders map[string]*memLease
+	waiters []*memLease
+}
+
+// memLease represents a holder or waiter kept alive until its expiry.
+type memLease struct {
+	id      string
+	permits int64
+	expiry  time.Time
+}
+
+// memSemaphoreBackend is an in-memory implementation of the semaphoreBackend interface.
+type memSemaphoreBackend struct {
+	name string
+}
+
+// NewMemorySemaphore creates a new in-memory semaphore allowing limit concurrent permits.
+// Semaphores with the same name share their state within the process.
+func NewMemorySemaphore(name string, limit int64, ttl time.Duration, opts ...Option) Semaphore {
+	return newSemaphore(name, limit, ttl, &memSemaphoreBackend{name: name}, opts...)
+}
+
+func (m *memSemaphoreBackend) acquire(holder, waiter string, n, limit int64, ttl, wait time.Duration) (bool, error) {
+	memSemaphores.Lock()
+	defer memSemaphores.Unlock()
+
+	entry := m.entry()
+	now := time.Now()
+
+	if waiter == "" {
+		if len(entry.waiters) > 0 {
+			return false, nil
+		}
+	} else {
+		idx := slices.IndexFunc(entry.waiters, func(w *memLease) bool { return w.id == waiter })
+		if idx < 0 {
+			entry.waiters = append(entry.waiters, &memLease{id: waiter})
+			idx = len(entry.waiters) - 1
+		}
+		entry.waiters[idx].expiry = now.Add(wait)
+
+		if idx != 0 {
+			return false, nil
+		}
+	}
+
+	var used int64
+	for _, h := range entry.holders {
+		used += h.permits
+	}
+
+	if used+n > limit {
+		return false, nil
+	}
+
+	lease, ok := entry.holders[holder]
+	if !ok {
+		lease = &memLease{id: holder}
+		entry.holders[holder] = lease
+	}
+	lease.permits += n
+	lease.expiry = now.Add(ttl)
+
+	if waiter != "" {
+		entry.waiters = entry.waiters[1:]
+	}
+
+	return true, nil
+}
+
+func (m *memSemaphoreBackend) release(holder string, n int64) (bool, error) {
+	memSemaphores.Lock()
+	defer memSemaphores.Unlock()
+
+	entry := m.entry()
+	lease, ok := entry.holders[holder]
+	if !ok {
+		return false, nil
+	}
+
+	lease.permits -= n
+	if lease.permits <= 0 {
+		delete(entry.holders, holder)
+	}
+
+	return true, nil
+}
+
+func (m *memSemaphoreBackend) renew(holder string, ttl time.Duration) (bool, error) {
+	memSemaphores.Lock()
+	defer memSemaphores.Unlock()
+
+	lease, ok := m.entry().holders[holder]
+	if !ok {
+		return false, nil
+	}
+
+	lease.expiry = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (m *memSemaphoreBackend) leave(waiter string) error {
+	memSemaphores.Lock()
+	defer memSemaphores.Unlock()
+
+	entry := m.entry()
+	entry.waiters = slices.DeleteFunc(entry.waiters, func(w *memLease) bool { return w.id == waiter })
+	return nil
+}
+
+// entry returns the semaphore state with expired holders and waiters removed.
+// It must be called with memSemaphores held.
+func (m *memSemaphoreBackend) entry() *memSemaphoreEntry {
+	entry, ok := memSemaphores.entries[m.name]
+	if !ok {
+		entry = &memSemaphoreEntry{holders: make(map[string]*memLease)}
+		memSemaphores.entries[m.name] = entry
+	}
+
+	now := time.Now()
+	for id, h := range entry.holders {
+		if !now.Before(h.expiry) {
+			delete(entry.holders, id)
+		}
+	}
+
+	entry.waiters = slices.DeleteFunc(entry.waiters, func(w *memLease) bool {
+		return !now.Before(w.expiry)
+	})
+
+	return entry
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// semaphoreAcquireScript grants permits to a holder in waiter order.
+	// Leases use the server time so that clients with skewed clocks agree.
+	// KEYS: holders, permits, waiters, waiters expiry, waiters sequence.
+	// ARGV: holder, waiter, permits, limit, ttl, wait.
+	semaphoreAcquireScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local expiry = now + tonumber(ARGV[5])
+
+for _, h in ipairs(redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", now)) do
+	redis.call("HDEL", KEYS[2], h)
+end
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+
+for _, w in ipairs(redis.call("ZRANGEBYSCORE", KEYS[4], "-inf", now)) do
+	redis.call("ZREM", KEYS[3], w)
+end
+redis.call("ZREMRANGEBYSCORE", KEYS[4], "-inf", now)
+
+local waiter = ARGV[2]
+local head = redis.call("ZRANGE", KEYS[3], 0, 0)[1]
+if waiter == "" then
+	if head then
+		return 0
+	end
+else
+	if not redis.call("ZSCORE", KEYS[3], waiter) then
+		redis.call("ZADD", KEYS[3], redis.call("INCR", KEYS[5]), waiter)
+		head = redis.call("ZRANGE", KEYS[3], 0, 0)[1]
+	end
+	redis.call("ZADD", KEYS[4], now + tonumber(ARGV[6]), waiter)
+	if head ~= waiter then
+		return 0
+	end
+end
+
+local used = 0
+for _, v in ipairs(redis.call("HVALS", KEYS[2])) do
+	used = used + tonumber(v)
+end
+
+local n = tonumber(ARGV[3])
+if used + n > tonumber(ARGV[4]) then
+	return 0
+end
+
+redis.call("HINCRBY", KEYS[2], ARGV[1], n)
+redis.call("ZADD", KEYS[1], expiry, ARGV[1])
+if waiter ~= "" then
+	redis.call("ZREM", KEYS[3], waiter)
+	redis.call("ZREM", KEYS[4], waiter)
+end
+return 1
+`)
+
+	// semaphoreReleaseScript returns permits held by a holder with a live lease.
+	// KEYS: holders, permits. ARGV: holder, permits.
+	semaphoreReleaseScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local expiry = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not expiry or tonumber(expiry) <= now then
+	return 0
+end
+if redis.call("HINCRBY", KEYS[2], ARGV[1], -tonumber(ARGV[2])) <= 0 then
+	redis.call("HDEL", KEYS[2], ARGV[1])
+	redis.call("ZREM", KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+	// semaphoreRenewScript extends the live lease of an existing holder.
+	// KEYS: holders. ARGV: holder, ttl.
+	semaphoreRenewScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local expiry = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if expiry and tonumber(expiry) > now then
+	redis.call("ZADD", KEYS[1], now + tonumber(ARGV[2]), ARGV[1])
+	return 1
+end
+return 0
+`)
+
+	// semaphoreLeaveScript removes a waiter from the queue.
+	// KEYS: waiters, waiters expiry. ARGV: waiter.
+	semaphoreLeaveScript = redis.NewScript(`
+redis.call("ZREM", KEYS[1], ARGV[1])
+redis.call("ZREM", KEYS[2], ARGV[1])
+return 1
+`)
+)
+
+// redisSemaphoreBackend is a Redis-based implementation of the semaphoreBackend interface
+// using sorted sets for holder leases and the waiter queue.
+type redisSemaphoreBackend struct {
+	holders  string
+	permits  string
+	waiters  string
+	expiries string
+	sequence string
+	client   redis.UniversalClient
+}
+
+// NewRedisSemaphore creates a new Redis semaphore allowing limit concurrent permits.
+// All instances sharing a name must use the same limit.
+func NewRedisSemaphore(name string, limit int64, ttl time.Duration, client redis.UniversalClient, opts ...Option) Semaphore {
+	base := hashTag(newOption(opts...).keyEncoder.Encode("semaphore", name))
+	return newSemaphore(name, limit, ttl, &redisSemaphoreBackend{
+		holders:  base + ":holders",
+		permits:  base + ":permits",
+		waiters:  base + ":waiters",
+		expiries: base + ":expiries",
+		sequence: base + ":sequence",
+		client:   client,
+	}, opts...)
+}
+
+func (r *redisSemaphoreBackend) acquire(holder, waiter string, n, limit int64, ttl, wait time.Duration) (bool, error) {
+	ok, err := semaphoreAcquireScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.holders, r.permits, r.waiters, r.expiries, r.sequence},
+		holder,
+		waiter,
+		n,
+		limit,
+		ttl.Milliseconds(),
+		wait.Milliseconds(),
+	).Bool()
+	return ok, redisError("acquire", r.holders, err)
+}
+
+func (r *redisSemaphoreBackend) release(holder string, n int64) (bool, error) {
+	ok, err := semaphoreReleaseScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.holders, r.permits},
+		holder,
+		n,
+	).Bool()
+	return ok, redisError("release", r.holders, err)
+}
+
+func (r *redisSemaphoreBackend) renew(holder string, ttl time.Duration) (bool, error) {
+	ok, err := semaphoreRenewScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.holders},
+		holder,
+		ttl.Milliseconds(),
+	).Bool()
+	return ok, redisError("renew", r.holders, err)
+}
+
+func (r *redisSemaphoreBackend) leave(waiter string) error {
+	err := semaphoreLeaveScript.Run(
+		context.Background(),
+		r.client,
+		[]string{r.waiters, r.expiries},
+		waiter,
+	).Err()
+	return redisError("leave", r.waiters, err)
+}
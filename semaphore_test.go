@@ -0,0 +1,197 @@
+package cache_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemaphore(t *testing.T) {
+	client := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{"localhost:6379"}})
+	factories := map[string]func(name string, limit int64, ttl time.Duration, opts ...cache.Option) cache.Semaphore{
+		"memory": cache.NewMemorySemaphore,
+		"redis": func(name string, limit int64, ttl time.Duration, opts ...cache.Option) cache.Semaphore {
+			return cache.NewRedisSemaphore(name, limit, ttl, client, opts...)
+		},
+	}
+
+	for name, newSemaphore := range factories {
+		t.Run(name, func(t *testing.T) {
+			retry := cache.WithRetry(5*time.Millisecond, 10*time.Millisecond)
+
+			t.Run("TryAcquire and Release", func(t *testing.T) {
+				first := newSemaphore("test-semaphore", 3, time.Minute)
+				second := newSemaphore("test-semaphore", 3, time.Minute)
+
+				ok, err := first.TryAcquire(2)
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				ok, err = second.TryAcquire(2)
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				ok, err = second.TryAcquire(1)
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				err = second.Release(2)
+				assert.Error(t, err)
+
+				require.NoError(t, first.Release(2))
+				require.NoError(t, second.Release(1))
+
+				_, err = first.TryAcquire(4)
+				assert.Error(t, err)
+			})
+
+			t.Run("Acquire with context", func(t *testing.T) {
+				first := newSemaphore("test-semaphore-ctx", 1, time.Minute)
+				second := newSemaphore("test-semaphore-ctx", 1, time.Minute, retry)
+
+				err := first.Acquire(context.Background(), 1)
+				require.NoError(t, err)
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				err = second.Acquire(ctx, 1)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+				// An abandoned waiter must not block later callers
+				require.NoError(t, first.Release(1))
+				ok, err := second.TryAcquire(1)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				require.NoError(t, second.Release(1))
+			})
+
+			t.Run("Fair ordering", func(t *testing.T) {
+				holder := newSemaphore("test-semaphore-fair", 2, time.Minute)
+				require.NoError(t, holder.Acquire(context.Background(), 2))
+
+				var mutex sync.Mutex
+				var order []int
+				var wg sync.WaitGroup
+				for i := range 3 {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						sem := newSemaphore("test-semaphore-fair", 2, time.Minute, retry)
+						assert.NoError(t, sem.Acquire(context.Background(), 2))
+
+						mutex.Lock()
+						order = append(order, i)
+						mutex.Unlock()
+						assert.NoError(t, sem.Release(2))
+					}()
+
+					// Give each waiter time to queue before the next one
+					time.Sleep(30 * time.Millisecond)
+				}
+
+				// A newcomer cannot skip the queue
+				ok, err := newSemaphore("test-semaphore-fair", 2, time.Minute).TryAcquire(1)
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				require.NoError(t, holder.Release(2))
+				wg.Wait()
+				assert.Equal(t, []int{0, 1, 2}, order)
+			})
+
+			t.Run("Lease renewal", func(t *testing.T) {
+				holder := newSemaphore("test-semaphore-lease", 1, 50*time.Millisecond)
+				ok, err := holder.TryAcquire(1)
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				// The lease is renewed while its holder is alive
+				time.Sleep(120 * time.Millisecond)
+				alive := newSemaphore("test-semaphore-lease", 1, time.Minute)
+				ok, err = alive.TryAcquire(1)
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				require.NoError(t, holder.Release(1))
+				ok, err = alive.TryAcquire(1)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				require.NoError(t, alive.Release(1))
+			})
+
+			t.Run("Slow waiter", func(t *testing.T) {
+				holder := newSemaphore("test-semaphore-slow", 1, 50*time.Millisecond)
+				require.NoError(t, holder.Acquire(context.Background(), 1))
+
+				// The waiter retries less often than the lease TTL
+				waiter := newSemaphore("test-semaphore-slow", 1, 50*time.Millisecond, cache.WithRetry(150*time.Millisecond, 150*time.Millisecond))
+				done := make(chan error, 1)
+				go func() { done <- waiter.Acquire(context.Background(), 1) }()
+
+				time.Sleep(90 * time.Millisecond)
+				require.NoError(t, holder.Release(1))
+
+				// The waiter keeps its place in line between attempts
+				ok, err := newSemaphore("test-semaphore-slow", 1, time.Minute).TryAcquire(1)
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				require.NoError(t, <-done)
+				require.NoError(t, waiter.Release(1))
+			})
+		})
+	}
+
+	t.Run("Lost lease", func(t *testing.T) {
+		name := uniquePrefix("semaphore-lost")
+		sem := cache.NewRedisSemaphore(name, 1, time.Minute, client)
+		ok, err := sem.TryAcquire(1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// Simulate the lease being taken away
+		err = client.Del(context.Background(), "{semaphore:"+name+"}:holders", "{semaphore:"+name+"}:permits").Err()
+		require.NoError(t, err)
+
+		assert.Error(t, sem.Release(1))
+
+		ok, err = sem.TryAcquire(1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		require.NoError(t, sem.Release(1))
+	})
+
+	t.Run("Renewal failures", func(t *testing.T) {
+		limiter := &failingLimiter{}
+		logs := &syncBuffer{}
+		sem := cache.NewRedisSemaphore(
+			uniquePrefix("semaphore-failing"), 1, 300*time.Millisecond,
+			redis.NewClient(&redis.Options{Limiter: limiter}),
+			cache.WithLogger(slog.New(slog.NewTextHandler(logs, nil))),
+		)
+
+		ok, err := sem.TryAcquire(1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// Wait for a successful renewal before the backend becomes unreachable
+		calls := limiter.calls.Load()
+		require.Eventually(t, func() bool { return limiter.calls.Load() > calls }, time.Second, time.Millisecond)
+		limiter.failing.Store(true)
+		failed := time.Now()
+
+		// The holder gives the permits up one renewal interval before the lease expires
+		require.Eventually(t, func() bool {
+			return strings.Contains(logs.String(), "semaphore lease lost")
+		}, time.Second, time.Millisecond)
+		assert.Less(t, time.Since(failed), 250*time.Millisecond)
+	})
+}
@@ -0,0 +1,65 @@
+package cache
+
+import "time"
+
+// SetCache is implemented by caches that store sets of unique string members.
+// The caches returned by NewMemoryCache and NewRedisCache implement it.
+//
+// The TTL applies to the whole set. Operations on keys holding another type of value
+// fail with ErrWrongType, as do Get and Lookup on sets, and sets whose last member
+// is removed are deleted.
+type SetCache interface {
+	// SAdd adds members to the set at key. If the key does not exist, the set is
+	// created with the specified TTL, or indefinitely if ttl is nil.
+	// Existing sets keep their TTL.
+	SAdd(key string, members []string, ttl *time.Duration) error
+
+	// SRem removes members from the set at key.
+	SRem(key string, members ...string) error
+
+	// SIsMember reports whether member belongs to the set at key.
+	SIsMember(key, member string) (bool, error)
+
+	// SMembers retrieves the members of the set at key in no particular order.
+	// Returns an empty slice if the key does not exist.
+	SMembers(key string) ([]string, error)
+
+	// SCard returns the number of members of the set at key.
+	SCard(key string) (int64, error)
+}
+
+// ScoredMember is a member of a sorted set with its score.
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// SortedSetCache is implemented by caches that store sets of unique string members
+// ordered by score. Members with equal scores are ordered lexicographically.
+// The caches returned by NewMemoryCache and NewRedisCache implement it.
+//
+// The TTL applies to the whole sorted set. Operations on keys holding another type
+// of value fail with ErrWrongType, as do Get and Lookup on sorted sets, and sorted sets
+// whose last member is removed are deleted.
+type SortedSetCache interface {
+	// ZAdd adds members with their scores to the sorted set at key, updating the
+	// scores of existing members. If the key does not exist, the sorted set is
+	// created with the specified TTL, or indefinitely if ttl is nil.
+	// Existing sorted sets keep their TTL.
+	ZAdd(key string, members map[string]float64, ttl *time.Duration) error
+
+	// ZIncrBy increments the score of member and returns the new score.
+	// Missing members start at zero and missing sorted sets are created without TTL.
+	ZIncrBy(key, member string, value float64) (float64, error)
+
+	// ZRange retrieves the members ranked from start to stop inclusive in ascending
+	// order of score. Negative ranks count from the highest score, -1 being the last member.
+	ZRange(key string, start, stop int64) ([]ScoredMember, error)
+
+	// ZRangeByScore retrieves the members with scores between min and max inclusive
+	// in ascending order of score.
+	ZRangeByScore(key string, min, max float64) ([]ScoredMember, error)
+
+	// ZRem removes members from the sorted set at key.
+	ZRem(key string, members ...string) error
+}
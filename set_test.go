@@ -0,0 +1,215 @@
+package cache_test
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCache(t *testing.T) {
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache("set "+time.Now().Format(time.RFC3339Nano), redis.NewClient(&redis.Options{})),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			sets, ok := c.(cache.SetCache)
+			require.True(t, ok)
+
+			ttl := time.Minute
+			err := sets.SAdd("tags", []string{"go", "redis", "go"}, &ttl)
+			require.NoError(t, err)
+			err = sets.SAdd("tags", []string{"cache"}, nil)
+			require.NoError(t, err)
+
+			remaining, err := c.TTL("tags")
+			require.NoError(t, err)
+			assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+
+			count, err := sets.SCard("tags")
+			require.NoError(t, err)
+			assert.EqualValues(t, 3, count)
+
+			_, err = c.Get("tags")
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+
+			members, err := sets.SMembers("tags")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"go", "redis", "cache"}, members)
+
+			ok, err = sets.SIsMember("tags", "go")
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			err = sets.SRem("tags", "go", "missing")
+			require.NoError(t, err)
+			ok, err = sets.SIsMember("tags", "go")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			err = sets.SRem("tags", "redis", "cache")
+			require.NoError(t, err)
+			exists, err := c.Exists("tags")
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			members, err = sets.SMembers("tags")
+			require.NoError(t, err)
+			assert.Empty(t, members)
+
+			count, err = sets.SCard("missing")
+			require.NoError(t, err)
+			assert.Zero(t, count)
+
+			err = c.Put("scalar", "value", nil)
+			require.NoError(t, err)
+			_, err = sets.SIsMember("scalar", "value")
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+		})
+	}
+
+	t.Run("Snapshot while writing", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		sets := c.(cache.SetCache)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 1000 {
+				member := strconv.Itoa(i)
+				assert.NoError(t, sets.SAdd("tags", []string{member, "go"}, nil))
+				assert.NoError(t, sets.SRem("tags", member))
+			}
+		}()
+
+		for writing := true; writing; {
+			select {
+			case <-done:
+				writing = false
+			default:
+				require.NoError(t, c.(cache.Snapshotter).Snapshot(io.Discard))
+			}
+		}
+
+		members, err := sets.SMembers("tags")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, members)
+	})
+}
+
+func TestSortedSetCache(t *testing.T) {
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache("zset "+time.Now().Format(time.RFC3339Nano), redis.NewClient(&redis.Options{})),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			sets, ok := c.(cache.SortedSetCache)
+			require.True(t, ok)
+
+			ttl := time.Minute
+			err := sets.ZAdd("scores", map[string]float64{"alice": 30, "bob": 10, "carol": 20, "dave": 20}, &ttl)
+			require.NoError(t, err)
+
+			remaining, err := c.TTL("scores")
+			require.NoError(t, err)
+			assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+
+			_, err = c.Get("scores")
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+
+			members, err := sets.ZRange("scores", 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, []cache.ScoredMember{
+				{Member: "bob", Score: 10},
+				{Member: "carol", Score: 20},
+				{Member: "dave", Score: 20},
+				{Member: "alice", Score: 30},
+			}, members)
+
+			members, err = sets.ZRange("scores", -2, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []cache.ScoredMember{{Member: "dave", Score: 20}, {Member: "alice", Score: 30}}, members)
+
+			members, err = sets.ZRange("scores", 5, 10)
+			require.NoError(t, err)
+			assert.Empty(t, members)
+
+			score, err := sets.ZIncrBy("scores", "bob", 15.5)
+			require.NoError(t, err)
+			assert.Equal(t, 25.5, score)
+
+			score, err = sets.ZIncrBy("scores", "erin", 5)
+			require.NoError(t, err)
+			assert.Equal(t, 5.0, score)
+
+			members, err = sets.ZRangeByScore("scores", 20, 26)
+			require.NoError(t, err)
+			assert.Equal(t, []cache.ScoredMember{
+				{Member: "carol", Score: 20},
+				{Member: "dave", Score: 20},
+				{Member: "bob", Score: 25.5},
+			}, members)
+
+			members, err = sets.ZRangeByScore("scores", math.Inf(-1), 10)
+			require.NoError(t, err)
+			assert.Equal(t, []cache.ScoredMember{{Member: "erin", Score: 5}}, members)
+
+			err = sets.ZRem("scores", "alice", "bob", "carol", "dave")
+			require.NoError(t, err)
+			members, err = sets.ZRange("scores", 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, []cache.ScoredMember{{Member: "erin", Score: 5}}, members)
+
+			err = sets.ZRem("scores", "erin")
+			require.NoError(t, err)
+			exists, err := c.Exists("scores")
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			err = c.Put("scalar", "value", nil)
+			require.NoError(t, err)
+			_, err = sets.ZIncrBy("scalar", "member", 1)
+			assert.ErrorIs(t, err, cache.ErrWrongType)
+		})
+	}
+
+	t.Run("Snapshot while writing", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		sets := c.(cache.SortedSetCache)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 1000 {
+				member := strconv.Itoa(i)
+				assert.NoError(t, sets.ZAdd("scores", map[string]float64{member: 1}, nil))
+				_, err := sets.ZIncrBy("scores", "total", 1)
+				assert.NoError(t, err)
+				assert.NoError(t, sets.ZRem("scores", member))
+			}
+		}()
+
+		for writing := true; writing; {
+			select {
+			case <-done:
+				writing = false
+			default:
+				require.NoError(t, c.(cache.Snapshotter).Snapshot(io.Discard))
+			}
+		}
+
+		members, err := sets.ZRange("scores", 0, -1)
+		require.NoError(t, err)
+		assert.Equal(t, []cache.ScoredMember{{Member: "total", Score: 1000}}, members)
+	})
+}
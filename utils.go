@@ -1,8 +1,14 @@
 package cache
 
 import (
+	crand "crypto/rand"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -27,11 +33,20 @@ func cacheKey(prefix string, keys ...string) string {
 	return key
 }
 
+// hashTag wraps a key in braces so that keys derived from it
+// share a Redis Cluster hash slot.
+func hashTag(key string) string {
+	return "{" + key + "}"
+}
+
+var (
+	rxChars  = regexp.MustCompile(`[^a-zA-Z0-9-]`)
+	rxSpaces = regexp.MustCompile(`\s+`)
+	rxDashes = regexp.MustCompile(`\-+`)
+)
+
 // slugify make slug-format-text from strings.
 func slugify(keys ...string) string {
-	rxChars := regexp.MustCompile(`[^a-zA-Z0-9-]`)
-	rxSpaces := regexp.MustCompile(`\s+`)
-	rxDashes := regexp.MustCompile(`\-+`)
 	content := strings.Join(keys, "-")
 	content = rxSpaces.ReplaceAllString(content, "-")
 	content = rxDashes.ReplaceAllString(content, "-")
@@ -54,3 +69,81 @@ func randomString(n uint, letters string) (string, error) {
 
 	return string(bytes), nil
 }
+
+// randomToken generate a random hex token suitable as an owner identity.
+func randomToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := crand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
+// formatValue encode value as text the same way Redis clients do.
+func formatValue(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte{}, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case int:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case uint:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(nil, v, 10), nil
+	case float32:
+		return strconv.AppendFloat(nil, float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return []byte("1"), nil
+		}
+		return []byte("0"), nil
+	case time.Time:
+		return v.AppendFormat(nil, time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.AppendInt(nil, v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCodec, err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %T", ErrCodec, value)
+	}
+}
+
+// seededHash hashes key with the given seed using FNV-1a
+// followed by a 64-bit finalizer to spread close seeds apart.
+func seededHash(seed uint64, key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	x := h.Sum64() ^ seed
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
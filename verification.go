@@ -31,28 +31,32 @@ type verification struct {
 	name  string
 	ttl   time.Duration
 	cache Cache
+	opt   option
 }
 
 // NewVerification creates a new instance of the verification code.
-func NewVerification(name string, ttl time.Duration, cache Cache) VerificationCode {
+func NewVerification(name string, ttl time.Duration, cache Cache, opts ...Option) VerificationCode {
 	return &verification{
 		name:  "verify " + name,
 		ttl:   ttl,
 		cache: cache,
+		opt:   newOption(opts...),
 	}
 }
 
-func (v *verification) Set(code string) error {
+func (v *verification) Set(code string) (err error) {
+	defer v.opt.observe("set", v.name, time.Now(), &err)
+
 	exists, err := v.cache.Update(v.name, code)
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		return v.cache.Put(v.name, code, &v.ttl)
+		err = v.cache.Put(v.name, code, &v.ttl)
 	}
 
-	return nil
+	return err
 }
 
 func (v *verification) Generate(count uint) (string, error) {
@@ -68,11 +72,15 @@ func (v *verification) Generate(count uint) (string, error) {
 	return code, nil
 }
 
-func (v *verification) Clear() error {
+func (v *verification) Clear() (err error) {
+	defer v.opt.observe("clear", v.name, time.Now(), &err)
+
 	return v.cache.Forget(v.name)
 }
 
-func (v *verification) Get() (string, error) {
+func (v *verification) Get() (_ string, err error) {
+	defer v.opt.observe("get", v.name, time.Now(), &err)
+
 	caster, err := v.cache.Cast(v.name)
 	if err != nil {
 		return "", err
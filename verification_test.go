@@ -5,12 +5,27 @@ import (
 	"time"
 
 	"github.com/go-universal/cache"
+	"github.com/go-universal/cache/cachetest"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestVerificationCode(t *testing.T) {
+	clock := cache.NewFakeClock(time.Now())
+	t.Run("Memory", func(t *testing.T) {
+		cachetest.RunVerificationSuite(t, func(t *testing.T) cache.Cache {
+			return cache.NewMemoryCache(cache.WithClock(clock))
+		}, cachetest.WithAdvance(clock.Advance))
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{})
+		cachetest.RunVerificationSuite(t, func(t *testing.T) cache.Cache {
+			return cache.NewRedisCache(uniquePrefix("suite"), client)
+		}, cachetest.WithoutExpiry())
+	})
+
 	redisCache := cache.NewRedisCache("test", redis.NewClient(&redis.Options{}))
 
 	// Create a new verification code manager
@@ -0,0 +1,377 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// writeBehindAttempts is the number of flushes tried when a write-behind cache is closed.
+const writeBehindAttempts = 5
+
+// errWritingClosed is returned by writes to a write-behind cache after Close.
+var errWritingClosed = errors.New("writing cache is closed")
+
+// Writer persists cache writes to a system of record.
+type Writer interface {
+	// Write persists the value of key.
+	Write(ctx context.Context, key string, value any) error
+
+	// Delete removes key from the system of record.
+	Delete(ctx context.Context, key string) error
+}
+
+// WritingCache is a Cache that forwards writes to a Writer.
+type WritingCache interface {
+	Cache
+
+	// Flush persists the pending write-behind changes once.
+	// Changes that fail are kept pending.
+	Flush(ctx context.Context) error
+
+	// Close stops the write-behind flushes and drains the pending changes.
+	// Write-behind writes fail once the cache is closed.
+	Close() error
+}
+
+// pendingWrite is a change waiting to be persisted by a write-behind cache.
+type pendingWrite struct {
+	value   any
+	deleted bool
+}
+
+// writingCache is a write-through and write-behind Cache decorator.
+type writingCache struct {
+	Cache
+	writer Writer
+	opt    option
+
+	mutex    sync.Mutex
+	pending  map[string]pendingWrite
+	closed   bool
+	flushing sync.Mutex
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewWritingCache creates a cache forwarding writes of cache to writer.
+//
+// By default writes are persisted synchronously (write-through): the writer is called
+// before the cache, and a failing writer fails the operation and leaves the cache
+// untouched. Increments are applied to the cache first and reverted if the writer fails.
+// With WithWriteBehind, changes are buffered and coalesced per key, then flushed every
+// interval or once size keys are pending, retrying failures with the backoff set by
+// WithRetry. Pending changes are drained on Close, and later writes fail.
+func NewWritingCache(cache Cache, writer Writer, opts ...Option) WritingCache {
+	w := &writingCache{
+		Cache:   cache,
+		writer:  writer,
+		opt:     newOption(opts...),
+		pending: make(map[string]pendingWrite),
+		kick:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if w.opt.writeBehind {
+		go w.flushLoop()
+	} else {
+		close(w.done)
+	}
+
+	return w
+}
+
+func (w *writingCache) Put(key string, value any, ttl *time.Duration) error {
+	_, err := w.apply(key, pendingWrite{value: value}, func() (bool, error) {
+		return true, w.Cache.Put(key, value, ttl)
+	})
+	return err
+}
+
+func (w *writingCache) Update(key string, value any) (bool, error) {
+	// Write-through must not persist keys the cache does not hold
+	if !w.opt.writeBehind {
+		exists, err := w.Cache.Exists(key)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+
+	return w.apply(key, pendingWrite{value: value}, func() (bool, error) {
+		return w.Cache.Update(key, value)
+	})
+}
+
+func (w *writingCache) PutOrUpdate(key string, value any, ttl *time.Duration) error {
+	_, err := w.apply(key, pendingWrite{value: value}, func() (bool, error) {
+		return true, w.Cache.PutOrUpdate(key, value, ttl)
+	})
+	return err
+}
+
+func (w *writingCache) Lookup(key string) (any, bool, error) {
+	return Lookup(w.Cache, key)
+}
+
+func (w *writingCache) Pull(key string) (any, error) {
+	var val any
+	_, err := w.apply(key, pendingWrite{deleted: true}, func() (ok bool, err error) {
+		val, err = w.Cache.Pull(key)
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (w *writingCache) Forget(key string) error {
+	_, err := w.apply(key, pendingWrite{deleted: true}, func() (bool, error) {
+		return true, w.Cache.Forget(key)
+	})
+	return err
+}
+
+func (w *writingCache) Increment(key string, value int64) (bool, error) {
+	return w.modify(
+		key,
+		func() (bool, error) { return w.Cache.Increment(key, value) },
+		func() (bool, error) { return w.Cache.Decrement(key, value) },
+	)
+}
+
+func (w *writingCache) Decrement(key string, value int64) (bool, error) {
+	return w.modify(
+		key,
+		func() (bool, error) { return w.Cache.Decrement(key, value) },
+		func() (bool, error) { return w.Cache.Increment(key, value) },
+	)
+}
+
+func (w *writingCache) IncrementFloat(key string, value float64) (bool, error) {
+	return w.modify(
+		key,
+		func() (bool, error) { return w.Cache.IncrementFloat(key, value) },
+		func() (bool, error) { return w.Cache.DecrementFloat(key, value) },
+	)
+}
+
+func (w *writingCache) DecrementFloat(key string, value float64) (bool, error) {
+	return w.modify(
+		key,
+		func() (bool, error) { return w.Cache.DecrementFloat(key, value) },
+		func() (bool, error) { return w.Cache.IncrementFloat(key, value) },
+	)
+}
+
+func (w *writingCache) Flush(ctx context.Context) error {
+	w.flushing.Lock()
+	defer w.flushing.Unlock()
+
+	w.mutex.Lock()
+	batch := w.pending
+	w.pending = make(map[string]pendingWrite)
+	w.mutex.Unlock()
+
+	var errs []error
+	for key, change := range batch {
+		err := w.write(ctx, key, change)
+		if err == nil {
+			continue
+		}
+
+		errs = append(errs, err)
+
+		// Keep the failed change unless a newer one was queued meanwhile
+		w.mutex.Lock()
+		if _, ok := w.pending[key]; !ok {
+			w.pending[key] = change
+		}
+		w.mutex.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *writingCache) Close() error {
+	if !w.opt.writeBehind {
+		return nil
+	}
+
+	w.once.Do(func() {
+		w.mutex.Lock()
+		w.closed = true
+		w.mutex.Unlock()
+		close(w.stop)
+	})
+	<-w.done
+
+	var err error
+	delay := time.Duration(0)
+	for attempt := range writeBehindAttempts {
+		if attempt > 0 {
+			delay = w.opt.backoff(delay)
+			time.Sleep(delay)
+		}
+
+		if err = w.Flush(context.Background()); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// apply performs a cache write that changes key to change.
+// Write-through persists the change first and leaves the cache untouched if the
+// writer fails. Write-behind queues the change once the cache write succeeds.
+// A false result from the cache write means that nothing was changed.
+func (w *writingCache) apply(key string, change pendingWrite, write func() (bool, error)) (bool, error) {
+	if !w.opt.writeBehind {
+		if err := w.write(context.Background(), key, change); err != nil {
+			return false, err
+		}
+		return write()
+	}
+
+	if err := w.open(key); err != nil {
+		return false, err
+	}
+
+	ok, err := write()
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	return true, w.enqueue(key, change)
+}
+
+// modify performs an in-place cache modification and persists the new value.
+// The new value is only known once applied, so write-through reverts the
+// modification with undo if the writer fails.
+func (w *writingCache) modify(key string, do, undo func() (bool, error)) (bool, error) {
+	if w.opt.writeBehind {
+		if err := w.open(key); err != nil {
+			return false, err
+		}
+	}
+
+	ok, err := do()
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	val, exists, err := Lookup(w.Cache, key)
+	if err != nil || !exists {
+		return exists, err
+	}
+
+	if w.opt.writeBehind {
+		return true, w.enqueue(key, pendingWrite{value: val})
+	}
+
+	if err := w.write(context.Background(), key, pendingWrite{value: val}); err != nil {
+		_, undoErr := undo()
+		return false, errors.Join(err, undoErr)
+	}
+
+	return true, nil
+}
+
+// open returns an error once a write-behind cache is closed.
+func (w *writingCache) open(key string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return &OpError{Op: "write", Key: key, Err: errWritingClosed}
+	}
+
+	return nil
+}
+
+// enqueue queues a change for the next write-behind flush.
+func (w *writingCache) enqueue(key string, change pendingWrite) error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return &OpError{Op: "write", Key: key, Err: errWritingClosed}
+	}
+	w.pending[key] = change
+	full := w.opt.flushSize > 0 && len(w.pending) >= w.opt.flushSize
+	w.mutex.Unlock()
+
+	if full {
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// write sends a single change to the writer.
+func (w *writingCache) write(ctx context.Context, key string, change pendingWrite) (err error) {
+	defer w.opt.observe("write", key, time.Now(), &err)
+
+	if change.deleted {
+		err = w.writer.Delete(ctx, key)
+	} else {
+		err = w.writer.Write(ctx, key, change.value)
+	}
+
+	if err != nil {
+		return &OpError{Op: "write", Key: key, Err: err}
+	}
+
+	return nil
+}
+
+// flushLoop flushes pending changes until the cache is closed,
+// waiting with backoff after failed flushes.
+func (w *writingCache) flushLoop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opt.flushInterval)
+	defer ticker.Stop()
+
+	delay := time.Duration(0)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		case <-w.kick:
+		}
+
+		if err := w.Flush(context.Background()); err == nil {
+			delay = 0
+			continue
+		}
+
+		delay = w.opt.backoff(delay)
+		w.opt.logger.Warn(
+			"cache write-behind flush failed",
+			slog.Duration("retry", delay),
+		)
+
+		select {
+		case <-w.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingWriter is a Writer keeping the persisted values in a map.
+type recordingWriter struct {
+	mutex  sync.Mutex
+	values map[string]any
+	writes int
+	fail   bool
+}
+
+func newRecordingWriter() *recordingWriter {
+	return &recordingWriter{values: make(map[string]any)}
+}
+
+func (r *recordingWriter) Write(ctx context.Context, key string, value any) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.fail {
+		return errors.New("writer unavailable")
+	}
+
+	r.writes++
+	r.values[key] = value
+	return nil
+}
+
+func (r *recordingWriter) Delete(ctx context.Context, key string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.fail {
+		return errors.New("writer unavailable")
+	}
+
+	delete(r.values, key)
+	return nil
+}
+
+func (r *recordingWriter) value(key string) (any, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	val, ok := r.values[key]
+	return val, ok
+}
+
+func (r *recordingWriter) setFail(fail bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.fail = fail
+}
+
+func TestWritingCache(t *testing.T) {
+	t.Run("Write-through", func(t *testing.T) {
+		writer := newRecordingWriter()
+		c := cache.NewWritingCache(cache.NewMemoryCache(), writer)
+		defer c.Close()
+
+		require.NoError(t, c.Put("key", "value", nil))
+		val, ok := writer.value("key")
+		assert.True(t, ok)
+		assert.Equal(t, "value", val)
+
+		require.NoError(t, c.Put("counter", 1, nil))
+		_, err := c.Increment("counter", 2)
+		require.NoError(t, err)
+		val, _ = writer.value("counter")
+		assert.EqualValues(t, 3, val)
+
+		require.NoError(t, c.Forget("key"))
+		_, ok = writer.value("key")
+		assert.False(t, ok)
+
+		writer.setFail(true)
+		err = c.Put("failing", "value", nil)
+		assert.Error(t, err)
+
+		exists, err := c.Exists("failing")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		// Failed writes leave the previous values cached
+		assert.Error(t, c.Put("counter", 10, nil))
+		assert.Error(t, c.Forget("counter"))
+		_, err = c.Increment("counter", 5)
+		assert.Error(t, err)
+
+		val, err = c.Get("counter")
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, val)
+	})
+
+	t.Run("Write-behind", func(t *testing.T) {
+		writer := newRecordingWriter()
+		c := cache.NewWritingCache(cache.NewMemoryCache(), writer, cache.WithWriteBehind(time.Hour, 0))
+
+		for i := range 5 {
+			require.NoError(t, c.Put("key", i, nil))
+		}
+		_, ok := writer.value("key")
+		assert.False(t, ok)
+
+		require.NoError(t, c.Flush(context.Background()))
+		val, _ := writer.value("key")
+		assert.Equal(t, 4, val)
+		assert.Equal(t, 1, writer.writes)
+
+		require.NoError(t, c.Put("drained", "value", nil))
+		require.NoError(t, c.Close())
+		val, _ = writer.value("drained")
+		assert.Equal(t, "value", val)
+
+		// Writes after Close would never be flushed
+		assert.Error(t, c.Put("late", "value", nil))
+		_, err := c.Increment("key", 1)
+		assert.Error(t, err)
+
+		exists, err := c.Exists("late")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Write-behind by size", func(t *testing.T) {
+		writer := newRecordingWriter()
+		c := cache.NewWritingCache(cache.NewMemoryCache(), writer, cache.WithWriteBehind(time.Hour, 2))
+		defer c.Close()
+
+		require.NoError(t, c.Put("first", 1, nil))
+		require.NoError(t, c.Put("second", 2, nil))
+
+		assert.Eventually(t, func() bool {
+			_, ok := writer.value("second")
+			return ok
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Write-behind retry", func(t *testing.T) {
+		writer := newRecordingWriter()
+		writer.setFail(true)
+		c := cache.NewWritingCache(
+			cache.NewMemoryCache(), writer,
+			cache.WithWriteBehind(10*time.Millisecond, 0),
+			cache.WithRetry(5*time.Millisecond, 20*time.Millisecond),
+		)
+		defer c.Close()
+
+		require.NoError(t, c.Put("key", "value", nil))
+		time.Sleep(30 * time.Millisecond)
+		writer.setFail(false)
+
+		assert.Eventually(t, func() bool {
+			_, ok := writer.value("key")
+			return ok
+		}, time.Second, 5*time.Millisecond)
+	})
+}